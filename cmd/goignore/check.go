@@ -0,0 +1,285 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dev-addict/goignore"
+)
+
+func check(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("check", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: goignore check [-v] [-n] [-z] [--stdin] [-f file]... [path...]")
+		flags.PrintDefaults()
+	}
+
+	var ignoreFiles stringsFlag
+	var verbose, nonMatching, nul, fromStdin bool
+
+	flags.Var(&ignoreFiles, "f", "ignore `file` to apply, can be repeated (default the .gitignore files of every path)")
+	flags.BoolVar(&verbose, "v", false, "show the source, line and pattern that decided every path")
+	flags.BoolVar(&verbose, "verbose", false, "same as -v")
+	flags.BoolVar(&nonMatching, "n", false, "show paths that match no pattern too, with -v")
+	flags.BoolVar(&nonMatching, "non-matching", false, "same as -n")
+	flags.BoolVar(&nul, "z", false, "separate input and output paths with NUL")
+	flags.BoolVar(&fromStdin, "stdin", false, "read paths from the standard input")
+
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return exitFatal
+	}
+
+	if nonMatching && !verbose {
+		fmt.Fprintln(stderr, "goignore: -n is only valid with -v")
+		return exitFatal
+	}
+
+	paths := flags.Args()
+	if fromStdin {
+		if len(paths) > 0 {
+			fmt.Fprintln(stderr, "goignore: cannot specify paths with --stdin")
+			return exitFatal
+		}
+
+		var err error
+		if paths, err = readPaths(stdin, nul); err != nil {
+			fmt.Fprintf(stderr, "goignore: %s\n", err.Error())
+			return exitFatal
+		}
+	}
+
+	if len(paths) == 0 {
+		fmt.Fprintln(stderr, "goignore: no path specified")
+		return exitFatal
+	}
+
+	var warnings goignore.ParseErrors
+	defer printWarnings(stderr, &warnings)
+
+	tree := goignore.NewTree(".gitignore")
+	tree.Options.Warnings = &warnings
+
+	trees, err := loadIgnoreFiles(ignoreFiles, tree.Options)
+	if err != nil {
+		fmt.Fprintf(stderr, "goignore: %s\n", err.Error())
+		return exitFatal
+	}
+	if len(ignoreFiles) == 0 {
+		trees = []*goignore.Tree{tree}
+	}
+
+	out := bufio.NewWriter(stdout)
+	defer out.Flush()
+
+	status := exitNotIgnored
+	loaded := map[string]bool{}
+
+	for _, path := range paths {
+		name, isDir, err := normalizePath(path)
+		if err != nil {
+			fmt.Fprintf(stderr, "goignore: %s\n", err.Error())
+			return exitFatal
+		}
+
+		// Like git, the .gitignore files of the parent directories apply when no ignore file is given.
+		if len(ignoreFiles) == 0 {
+			if err := loadParents(tree, name, loaded); err != nil {
+				fmt.Fprintf(stderr, "goignore: %s\n", err.Error())
+				return exitFatal
+			}
+		}
+
+		explanation, err := explain(trees, name, isDir)
+		if err != nil {
+			fmt.Fprintf(stderr, "goignore: %s\n", err.Error())
+			return exitFatal
+		}
+
+		// Like git check-ignore, a path decided by a negated pattern counts as a match with -v.
+		if explanation.Ignored || verbose && explanation.Pattern != nil {
+			status = exitIgnored
+		}
+
+		switch {
+		case verbose && explanation.Pattern != nil:
+			printVerbose(out, nul, explanation.Pattern.Source, fmt.Sprint(explanation.Pattern.Line), explanation.Pattern.Text, path)
+		case verbose && nonMatching:
+			printVerbose(out, nul, "", "", "", path)
+		case !verbose && explanation.Ignored:
+			printPath(out, nul, path)
+		}
+	}
+
+	return status
+}
+
+// loadIgnoreFiles loads the ignore files into a Tree per Dialect, so the patterns of different dialects are never
+// joined in one Rules. Every file is anchored to its directory relative to the working directory.
+func loadIgnoreFiles(ignoreFiles []string, options goignore.ParseOptions) ([]*goignore.Tree, error) {
+	var trees []*goignore.Tree
+	byDialect := map[string]*goignore.Tree{}
+
+	for _, ignoreFile := range ignoreFiles {
+		dialect, ok := goignore.LookupDialect(filepath.Base(ignoreFile))
+		if !ok {
+			dialect = goignore.DialectGit
+		}
+		options.Dialect = dialect
+
+		rules, err := goignore.ParseFileFromPathWithOptions(ignoreFile, options)
+		if err != nil {
+			return nil, err
+		}
+
+		dir, err := relPath(filepath.Dir(ignoreFile))
+		if err != nil {
+			return nil, err
+		}
+
+		tree, ok := byDialect[dialect.Name()]
+		if !ok {
+			tree = goignore.NewTree(filepath.Base(ignoreFile))
+			byDialect[dialect.Name()] = tree
+			trees = append(trees, tree)
+		}
+
+		tree.Add(dir, rules)
+	}
+
+	return trees, nil
+}
+
+// explain returns the explanation of the last Tree with a pattern matching the path.
+func explain(trees []*goignore.Tree, name string, isDir bool) (goignore.Explanation, error) {
+	explanation := goignore.Explanation{Index: -1}
+
+	for _, tree := range trees {
+		treeExplanation, err := tree.ExplainPath(name, isDir)
+		if err != nil {
+			return goignore.Explanation{Index: -1}, err
+		}
+
+		if treeExplanation.Pattern != nil {
+			explanation = treeExplanation
+		}
+	}
+
+	return explanation, nil
+}
+
+// loadParents loads the ignore files of the working directory and of every parent directory of the path.
+func loadParents(tree *goignore.Tree, name string, loaded map[string]bool) error {
+	segments := strings.Split(name, "/")
+
+	for i := 0; i < len(segments); i++ {
+		dir := strings.Join(segments[:i], "/")
+		if loaded[dir] {
+			continue
+		}
+		loaded[dir] = true
+
+		if dir != "" {
+			if info, err := os.Stat(filepath.FromSlash(dir)); err != nil || !info.IsDir() {
+				continue
+			}
+		}
+
+		if err := tree.Load(".", dir); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func printWarnings(w io.Writer, warnings *goignore.ParseErrors) {
+	for _, warning := range *warnings {
+		fmt.Fprintf(w, "warning: %s\n", warning.Error())
+	}
+}
+
+func readPaths(r io.Reader, nul bool) ([]string, error) {
+	s := bufio.NewScanner(r)
+	if nul {
+		s.Split(scanNul)
+	}
+
+	var paths []string
+	for s.Scan() {
+		if path := s.Text(); path != "" {
+			paths = append(paths, path)
+		}
+	}
+
+	return paths, s.Err()
+}
+
+func scanNul(data []byte, atEOF bool) (int, []byte, error) {
+	if i := bytes.IndexByte(data, 0); i >= 0 {
+		return i + 1, data[:i], nil
+	}
+	if atEOF && len(data) > 0 {
+		return len(data), data, nil
+	}
+
+	return 0, nil, nil
+}
+
+func normalizePath(path string) (string, bool, error) {
+	isDir := strings.HasSuffix(filepath.ToSlash(path), "/")
+	if info, err := os.Stat(path); err == nil {
+		isDir = info.IsDir()
+	}
+
+	name, err := relPath(path)
+	if err != nil {
+		return "", false, err
+	}
+
+	return name, isDir, nil
+}
+
+// relPath returns the slash-separated path relative to the working directory.
+func relPath(path string) (string, error) {
+	if filepath.IsAbs(path) {
+		wd, err := os.Getwd()
+		if err != nil {
+			return "", err
+		}
+
+		if path, err = filepath.Rel(wd, path); err != nil {
+			return "", err
+		}
+	}
+
+	return filepath.ToSlash(filepath.Clean(path)), nil
+}
+
+func printVerbose(w io.Writer, nul bool, source, line, pattern, path string) {
+	if nul {
+		fmt.Fprintf(w, "%s\x00%s\x00%s\x00%s\x00", source, line, pattern, path)
+		return
+	}
+
+	fmt.Fprintf(w, "%s:%s:%s\t%s\n", source, line, pattern, path)
+}
+
+func printPath(w io.Writer, nul bool, path string) {
+	if nul {
+		fmt.Fprintf(w, "%s\x00", path)
+		return
+	}
+
+	fmt.Fprintln(w, path)
+}
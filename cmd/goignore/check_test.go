@@ -0,0 +1,217 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeIgnoreFile(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), ".gitignore")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+
+	return path
+}
+
+// chdir changes the working directory for the rest of the test, since ignore files apply to their own directory.
+func chdir(t *testing.T, dir string) {
+	t.Helper()
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+
+	t.Cleanup(func() {
+		if err := os.Chdir(wd); err != nil {
+			t.Fatalf("Unexpected error: %s", err.Error())
+		}
+	})
+}
+
+func TestCheck(t *testing.T) {
+	ignoreFile := writeIgnoreFile(t, "*.log\n!important.log\nbuild/\n")
+	chdir(t, filepath.Dir(ignoreFile))
+
+	t.Run("should print ignored paths", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+
+		status := run([]string{"check", "-f", ignoreFile, "debug.log", "important.log", "main.go", "build/"}, nil, &stdout, &stderr)
+		if status != exitIgnored {
+			t.Errorf("Expected status %d, got %d", exitIgnored, status)
+		}
+
+		expected := "debug.log\nbuild/\n"
+		if stdout.String() != expected {
+			t.Errorf("Expected %q, got %q", expected, stdout.String())
+		}
+	})
+
+	t.Run("should exit with 1 without ignored paths", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+
+		status := run([]string{"check", "-f", ignoreFile, "important.log", "main.go"}, nil, &stdout, &stderr)
+		if status != exitNotIgnored {
+			t.Errorf("Expected status %d, got %d", exitNotIgnored, status)
+		}
+
+		if stdout.String() != "" {
+			t.Errorf("Expected empty output, got %q", stdout.String())
+		}
+	})
+
+	t.Run("should print deciding patterns", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+
+		status := run([]string{"check", "-v", "-n", "-f", ignoreFile, "debug.log", "important.log", "main.go"}, nil, &stdout, &stderr)
+		if status != exitIgnored {
+			t.Errorf("Expected status %d, got %d", exitIgnored, status)
+		}
+
+		expected := ignoreFile + ":1:*.log\tdebug.log\n" +
+			ignoreFile + ":2:!important.log\timportant.log\n" +
+			"::\tmain.go\n"
+		if stdout.String() != expected {
+			t.Errorf("Expected %q, got %q", expected, stdout.String())
+		}
+	})
+
+	t.Run("should exit with 0 for negated patterns with -v", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+
+		status := run([]string{"check", "-v", "-f", ignoreFile, "important.log"}, nil, &stdout, &stderr)
+		if status != exitIgnored {
+			t.Errorf("Expected status %d, got %d", exitIgnored, status)
+		}
+
+		expected := ignoreFile + ":2:!important.log\timportant.log\n"
+		if stdout.String() != expected {
+			t.Errorf("Expected %q, got %q", expected, stdout.String())
+		}
+	})
+
+	t.Run("should read NUL separated paths from stdin", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+
+		stdin := strings.NewReader("debug.log\x00main.go\x00build/x\x00")
+
+		status := run([]string{"check", "--stdin", "-z", "-f", ignoreFile}, stdin, &stdout, &stderr)
+		if status != exitIgnored {
+			t.Errorf("Expected status %d, got %d", exitIgnored, status)
+		}
+
+		expected := "debug.log\x00build/x\x00"
+		if stdout.String() != expected {
+			t.Errorf("Expected %q, got %q", expected, stdout.String())
+		}
+	})
+
+	t.Run("should warn about invalid lines", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+
+		invalidIgnoreFile := writeIgnoreFile(t, "[123\n*.log\n")
+		debugLog := filepath.Join(filepath.Dir(invalidIgnoreFile), "debug.log")
+
+		status := run([]string{"check", "-f", invalidIgnoreFile, debugLog}, nil, &stdout, &stderr)
+		if status != exitIgnored {
+			t.Errorf("Expected status %d, got %d", exitIgnored, status)
+		}
+
+		if !strings.Contains(stderr.String(), "warning: "+invalidIgnoreFile+":1:1: syntax error in pattern") {
+			t.Errorf("Expected warning, got %q", stderr.String())
+		}
+	})
+
+	t.Run("should apply ignore files to their directory", func(t *testing.T) {
+		root := createTree(t, map[string]string{
+			".gitignore":     "*.log\n",
+			"sub/.gitignore": "/x\n!keep.log\n",
+			"sub/x":          "",
+			"x":              "",
+		})
+		chdir(t, root)
+
+		cases := []struct {
+			args     []string
+			status   int
+			expected string
+		}{
+			{[]string{"check", "-v", "sub/x"}, exitIgnored, filepath.Join("sub", ".gitignore") + ":1:/x\tsub/x\n"},
+			{[]string{"check", "-v", "-f", "sub/.gitignore", "sub/x"}, exitIgnored, "sub/.gitignore:1:/x\tsub/x\n"},
+			{[]string{"check", "x", "sub/debug.log", "sub/keep.log"}, exitIgnored, "sub/debug.log\n"},
+			{[]string{"check", "-f", "sub/.gitignore", "x"}, exitNotIgnored, ""},
+		}
+
+		for _, c := range cases {
+			var stdout, stderr bytes.Buffer
+
+			status := run(c.args, nil, &stdout, &stderr)
+			if status != c.status {
+				t.Errorf("%v: expected status %d, got %d: %s", c.args, c.status, status, stderr.String())
+			}
+
+			if stdout.String() != c.expected {
+				t.Errorf("%v: expected %q, got %q", c.args, c.expected, stdout.String())
+			}
+		}
+	})
+
+	t.Run("should keep the precedence of every dialect", func(t *testing.T) {
+		root := createTree(t, map[string]string{
+			".gitignore":    "*.log\n",
+			".dockerignore": "docs\n!docs/x.md\n",
+		})
+		chdir(t, root)
+
+		var stdout, stderr bytes.Buffer
+
+		status := run([]string{"check", "-f", ".gitignore", "-f", ".dockerignore", "docs/x.md", "docs/y.md", "a.log"}, nil, &stdout, &stderr)
+		if status != exitIgnored {
+			t.Errorf("Expected status %d, got %d: %s", exitIgnored, status, stderr.String())
+		}
+
+		expected := "docs/y.md\na.log\n"
+		if stdout.String() != expected {
+			t.Errorf("Expected %q, got %q", expected, stdout.String())
+		}
+	})
+
+	t.Run("should not fail without ignore files", func(t *testing.T) {
+		chdir(t, t.TempDir())
+
+		var stdout, stderr bytes.Buffer
+
+		status := run([]string{"check", "debug.log"}, nil, &stdout, &stderr)
+		if status != exitNotIgnored {
+			t.Errorf("Expected status %d, got %d: %s", exitNotIgnored, status, stderr.String())
+		}
+	})
+
+	t.Run("should fail with missing ignore file", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+
+		status := run([]string{"check", "-f", filepath.Join(t.TempDir(), ".gitignore"), "debug.log"}, nil, &stdout, &stderr)
+		if status != exitFatal {
+			t.Errorf("Expected status %d, got %d", exitFatal, status)
+		}
+	})
+
+	t.Run("should fail without paths", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+
+		status := run([]string{"check", "-f", ignoreFile}, nil, &stdout, &stderr)
+		if status != exitFatal {
+			t.Errorf("Expected status %d, got %d", exitFatal, status)
+		}
+	})
+}
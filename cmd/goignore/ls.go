@@ -0,0 +1,149 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"path/filepath"
+
+	"github.com/dev-addict/goignore"
+)
+
+func ls(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("ls", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: goignore ls [--ignored] [-z] [--format plain|nul|json] [--name name] [dir]")
+		flags.PrintDefaults()
+	}
+
+	var ignored, nul bool
+	var format, name string
+
+	flags.BoolVar(&ignored, "ignored", false, "list the ignored paths instead")
+	flags.BoolVar(&nul, "z", false, "same as --format nul")
+	flags.StringVar(&format, "format", "plain", "output `format`, one of plain, nul or json")
+	flags.StringVar(&name, "name", ".gitignore", "`name` of the ignore files")
+
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return exitFatal
+	}
+
+	if nul {
+		format = "nul"
+	}
+
+	if format != "plain" && format != "nul" && format != "json" {
+		fmt.Fprintf(stderr, "goignore: unknown format %q\n", format)
+		return exitFatal
+	}
+
+	if flags.NArg() > 1 {
+		fmt.Fprintln(stderr, "goignore: too many directories")
+		return exitFatal
+	}
+
+	root := "."
+	if flags.NArg() == 1 {
+		root = flags.Arg(0)
+	}
+
+	paths, err := list(root, name, ignored, stderr)
+	if err != nil {
+		fmt.Fprintf(stderr, "goignore: %s\n", err.Error())
+		return exitFatal
+	}
+
+	out := bufio.NewWriter(stdout)
+	defer out.Flush()
+
+	switch format {
+	case "json":
+		if err := json.NewEncoder(out).Encode(paths); err != nil {
+			fmt.Fprintf(stderr, "goignore: %s\n", err.Error())
+			return exitFatal
+		}
+	default:
+		for _, path := range paths {
+			printPath(out, format == "nul", path)
+		}
+	}
+
+	return 0
+}
+
+// list returns the files of the tree that are not ignored, or the ignored files and directories. The
+// .git directory is always skipped, and ignored directories are listed with a trailing slash.
+func list(root, name string, ignored bool, stderr io.Writer) ([]string, error) {
+	var warnings goignore.ParseErrors
+
+	tree := goignore.NewTree(name)
+	tree.Options.Warnings = &warnings
+	paths := []string{}
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() && d.Name() == ".git" && path != root {
+			return filepath.SkipDir
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		if path != root {
+			matched, err := tree.MatchPath(rel, d.IsDir())
+			if err != nil {
+				return err
+			}
+
+			if matched {
+				if !ignored {
+					return skip(d)
+				}
+
+				if d.IsDir() {
+					paths = append(paths, path+"/")
+				} else {
+					paths = append(paths, path)
+				}
+				return skip(d)
+			}
+		}
+
+		if d.IsDir() {
+			return tree.Load(root, rel)
+		}
+
+		if !ignored {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+
+	for _, warning := range warnings {
+		fmt.Fprintf(stderr, "warning: %s\n", warning.Error())
+	}
+
+	return paths, err
+}
+
+func skip(d fs.DirEntry) error {
+	if d.IsDir() {
+		return filepath.SkipDir
+	}
+
+	return nil
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func createTree(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	root := t.TempDir()
+
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("Unexpected error: %s", err.Error())
+		}
+
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("Unexpected error: %s", err.Error())
+		}
+	}
+
+	return root
+}
+
+func TestLs(t *testing.T) {
+	root := createTree(t, map[string]string{
+		".gitignore":        "*.log\nbuild/\n",
+		".git/HEAD":         "",
+		"main.go":           "",
+		"debug.log":         "",
+		"build/main":        "",
+		"src/.gitignore":    "!important.log\n/gen/\n",
+		"src/app.go":        "",
+		"src/gen/gen.go":    "",
+		"src/important.log": "",
+	})
+
+	t.Run("should list not ignored files", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+
+		status := run([]string{"ls", root}, nil, &stdout, &stderr)
+		if status != 0 {
+			t.Errorf("Expected status 0, got %d: %s", status, stderr.String())
+		}
+
+		expected := filepath.Join(root, ".gitignore") + "\n" +
+			filepath.Join(root, "main.go") + "\n" +
+			filepath.Join(root, "src", ".gitignore") + "\n" +
+			filepath.Join(root, "src", "app.go") + "\n" +
+			filepath.Join(root, "src", "important.log") + "\n"
+		if stdout.String() != expected {
+			t.Errorf("Expected %q, got %q", expected, stdout.String())
+		}
+	})
+
+	t.Run("should list ignored files and directories", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+
+		status := run([]string{"ls", "--ignored", "-z", root}, nil, &stdout, &stderr)
+		if status != 0 {
+			t.Errorf("Expected status 0, got %d: %s", status, stderr.String())
+		}
+
+		expected := filepath.Join(root, "build") + "/\x00" +
+			filepath.Join(root, "debug.log") + "\x00" +
+			filepath.Join(root, "src", "gen") + "/\x00"
+		if stdout.String() != expected {
+			t.Errorf("Expected %q, got %q", expected, stdout.String())
+		}
+	})
+
+	t.Run("should list files as json", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+
+		status := run([]string{"ls", "--format", "json", filepath.Join(root, "src")}, nil, &stdout, &stderr)
+		if status != 0 {
+			t.Errorf("Expected status 0, got %d: %s", status, stderr.String())
+		}
+
+		expected := `["` + filepath.ToSlash(filepath.Join(root, "src", ".gitignore")) + `","` +
+			filepath.ToSlash(filepath.Join(root, "src", "app.go")) + `","` +
+			filepath.ToSlash(filepath.Join(root, "src", "important.log")) + "\"]\n"
+		if stdout.String() != expected {
+			t.Errorf("Expected %q, got %q", expected, stdout.String())
+		}
+	})
+
+	t.Run("should warn about invalid lines", func(t *testing.T) {
+		root := createTree(t, map[string]string{
+			"src/.gitignore": "[123\n*.log\n",
+			"src/debug.log":  "",
+			"src/main.go":    "",
+		})
+
+		var stdout, stderr bytes.Buffer
+
+		status := run([]string{"ls", root}, nil, &stdout, &stderr)
+		if status != 0 {
+			t.Errorf("Expected status 0, got %d: %s", status, stderr.String())
+		}
+
+		expected := filepath.Join(root, "src", ".gitignore") + "\n" + filepath.Join(root, "src", "main.go") + "\n"
+		if stdout.String() != expected {
+			t.Errorf("Expected %q, got %q", expected, stdout.String())
+		}
+
+		if !strings.Contains(stderr.String(), "warning: "+filepath.Join(root, "src", ".gitignore")+":1:1: syntax error in pattern") {
+			t.Errorf("Expected warning, got %q", stderr.String())
+		}
+	})
+
+	t.Run("should fail with unknown format", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+
+		status := run([]string{"ls", "--format", "xml", root}, nil, &stdout, &stderr)
+		if status != exitFatal {
+			t.Errorf("Expected status %d, got %d", exitFatal, status)
+		}
+	})
+}
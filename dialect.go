@@ -0,0 +1,108 @@
+package goignore
+
+import (
+	"strings"
+	"sync"
+)
+
+type Dialect interface {
+	Name() string
+	ParseLine(line string) (*Pattern, error)
+	Match(pattern *Pattern, path string, isDir bool) (bool, error)
+	Precedence() Precedence
+}
+
+// LineParser parses the lines of a single ignore file.
+type LineParser interface {
+	ParseLine(line string) (*Pattern, error)
+}
+
+// StatefulDialect is implemented by dialects whose lines depend on the lines before them, like the "syntax:"
+// switches of Mercurial. A new LineParser is created for every parsed file.
+type StatefulDialect interface {
+	Dialect
+	NewLineParser() LineParser
+}
+
+type Precedence int
+
+const (
+	PrecedenceGit Precedence = iota
+	PrecedenceLastMatch
+)
+
+var (
+	DialectGit      Dialect = gitDialect{name: "git", doubleStar: true}
+	DialectDocker   Dialect = dockerDialect{}
+	DialectNpm      Dialect = gitDialect{name: "npm", doubleStar: true}
+	DialectESLint   Dialect = gitDialect{name: "eslint", doubleStar: true}
+	DialectPrettier Dialect = gitDialect{name: "prettier", doubleStar: true}
+	DialectHelm     Dialect = gitDialect{name: "helm"}
+	DialectGcloud   Dialect = gitDialect{name: "gcloud", doubleStar: true}
+	DialectHg       Dialect = hgDialect{}
+)
+
+var (
+	dialectsMu sync.RWMutex
+	dialects   = map[string]Dialect{}
+)
+
+func init() {
+	for _, dialect := range []Dialect{DialectGit, DialectDocker, DialectNpm, DialectESLint, DialectPrettier, DialectHelm, DialectGcloud, DialectHg} {
+		RegisterDialect(dialect)
+	}
+}
+
+func RegisterDialect(dialect Dialect) {
+	dialectsMu.Lock()
+	defer dialectsMu.Unlock()
+
+	dialects[dialect.Name()] = dialect
+}
+
+func LookupDialect(name string) (Dialect, bool) {
+	dialectsMu.RLock()
+	defer dialectsMu.RUnlock()
+
+	if dialect, ok := dialects[name]; ok {
+		return dialect, true
+	}
+
+	// Ignore file names are looked up by the name of their dialect, e.g. ".dockerignore" is "docker".
+	if strings.HasPrefix(name, ".") && strings.HasSuffix(name, "ignore") {
+		dialect, ok := dialects[strings.TrimSuffix(strings.TrimPrefix(name, "."), "ignore")]
+		return dialect, ok
+	}
+
+	return nil, false
+}
+
+type gitDialect struct {
+	name       string
+	doubleStar bool
+}
+
+func (d gitDialect) Name() string {
+	return d.name
+}
+
+func (d gitDialect) ParseLine(line string) (*Pattern, error) {
+	return parseGitLine(line, d.doubleStar)
+}
+
+func (d gitDialect) Match(pattern *Pattern, path string, isDir bool) (bool, error) {
+	glob := pattern.compiled()
+	return glob.match(newTarget(path, isDir))
+}
+
+func (d gitDialect) Precedence() Precedence {
+	return PrecedenceGit
+}
+
+func (r *Rules) dialect() Dialect {
+	if len(*r) == 0 || (*r)[0].dialect == nil {
+		return DialectGit
+	}
+
+	return (*r)[0].dialect
+}
@@ -0,0 +1,175 @@
+package goignore
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+type dockerDialect struct{}
+
+func (dockerDialect) Name() string {
+	return "docker"
+}
+
+func (dockerDialect) ParseLine(line string) (*Pattern, error) {
+	if strings.HasPrefix(line, "#") {
+		return nil, nil
+	}
+
+	rule := strings.TrimSpace(line)
+	if rule == "" {
+		return nil, nil
+	}
+
+	pattern := Pattern{
+		Text: line,
+	}
+
+	if strings.HasPrefix(rule, "!") {
+		pattern.IsNegate = true
+		rule = strings.TrimSpace(strings.TrimPrefix(rule, "!"))
+
+		// Like the Docker CLI, an exclusion without a pattern is illegal.
+		if rule == "" {
+			return nil, &ParseError{
+				Column: strings.Index(line, "!") + 1,
+				Text:   line,
+				Err:    fmt.Errorf("%w: illegal exclusion pattern: %q", ErrBadPattern, "!"),
+			}
+		}
+	}
+
+	// Like the Docker CLI, patterns are cleaned and always relative to the root of the context.
+	if rule != "" {
+		rule = path.Clean(rule)
+		if len(rule) > 1 {
+			rule = strings.TrimPrefix(rule, "/")
+		}
+	}
+
+	if _, err := matchName(rule, "test"); err != nil {
+		offset := strings.Index(line, rule)
+		if offset < 0 {
+			offset = 0
+		}
+
+		return nil, &ParseError{
+			Column: offset + badPatternOffset(rule) + 1,
+			Text:   line,
+			Err:    err,
+		}
+	}
+
+	re, err := compileDockerPattern(rule)
+	if err != nil {
+		return nil, &ParseError{
+			Column: strings.Index(line, rule) + 1,
+			Text:   line,
+			Err:    err,
+		}
+	}
+
+	pattern.Raw = rule
+	pattern.regexp = re
+
+	return &pattern, nil
+}
+
+func (dockerDialect) Match(pattern *Pattern, path string, isDir bool) (bool, error) {
+	if pattern.regexp == nil {
+		if _, err := matchName(pattern.Raw, "test"); err != nil {
+			return false, err
+		}
+
+		re, err := compileDockerPattern(pattern.Raw)
+		if err != nil {
+			return false, err
+		}
+		if pattern.IgnoreCase {
+			re = foldRegexp(re)
+		}
+
+		return matchDocker(re, path), nil
+	}
+
+	return matchDocker(pattern.regexp, path), nil
+}
+
+func (dockerDialect) Precedence() Precedence {
+	return PrecedenceLastMatch
+}
+
+// compileDockerPattern translates the pattern to a regexp like the Docker CLI, where "**" matches any number of
+// characters including the separator. Its errors are ErrBadPattern.
+func compileDockerPattern(pattern string) (*regexp.Regexp, error) {
+	var b strings.Builder
+	b.WriteString("^")
+
+	for i := 0; i < len(pattern); {
+		switch pattern[i] {
+		case '*':
+			i++
+			if i < len(pattern) && pattern[i] == '*' {
+				i++
+
+				if i < len(pattern) && pattern[i] == '/' {
+					i++
+					b.WriteString("(.*/)?")
+				} else {
+					b.WriteString(".*")
+				}
+			} else {
+				b.WriteString("[^/]*")
+			}
+		case '?':
+			b.WriteString("[^/]")
+			i++
+		case '[':
+			class, n, err := translateClass(pattern[i:])
+			if err != nil {
+				return nil, err
+			}
+			b.WriteString(class)
+			i += n
+		case '\\':
+			i++
+			if i == len(pattern) {
+				return nil, ErrBadPattern
+			}
+			fallthrough
+		default:
+			r, size := utf8.DecodeRuneInString(pattern[i:])
+			b.WriteString(regexp.QuoteMeta(string(r)))
+			i += size
+		}
+	}
+
+	b.WriteString("$")
+
+	re, err := regexp.Compile(b.String())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrBadPattern, err)
+	}
+
+	return re, nil
+}
+
+// matchDocker returns true if the regexp matches the path or one of its parent directories.
+func matchDocker(re *regexp.Regexp, name string) bool {
+	name = path.Clean(name)
+	if name == "." {
+		return false
+	}
+
+	segments := splitSegments(strings.TrimPrefix(name, "/"))
+	for i := 1; i <= len(segments); i++ {
+		if re.MatchString(strings.Join(segments[:i], "/")) {
+			return true
+		}
+	}
+
+	return false
+}
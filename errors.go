@@ -3,9 +3,61 @@ package goignore
 import (
 	"errors"
 	"path/filepath"
+	"strconv"
+	"strings"
 )
 
 var (
-	ErrDoubleStarSyntax = errors.New("double star syntax is not supported")
-	ErrBadPattern       = filepath.ErrBadPattern
+	ErrDoubleStarSyntax  = errors.New("double star syntax is not supported")
+	ErrBadPattern        = filepath.ErrBadPattern
+	ErrUnsupportedSyntax = errors.New("syntax is not supported")
 )
+
+type ParseError struct {
+	Source string
+	Line   int
+	Column int
+	Text   string
+	Err    error
+}
+
+func (e *ParseError) Error() string {
+	position := e.Source
+	if e.Line > 0 {
+		if position != "" {
+			position += ":"
+		}
+		position += strconv.Itoa(e.Line) + ":" + strconv.Itoa(e.Column)
+	}
+
+	message := e.Err.Error() + ": " + e.Text
+	if position == "" {
+		return message
+	}
+
+	return position + ": " + message
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+type ParseErrors []*ParseError
+
+func (e ParseErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+
+	return strings.Join(messages, "\n")
+}
+
+func (e ParseErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+
+	return errs
+}
@@ -0,0 +1,63 @@
+package goignore
+
+import "strings"
+
+type Explanation struct {
+	Ignored  bool
+	Pattern  *Pattern
+	Index    int
+	IsNegate bool
+	Matches  []Pattern
+}
+
+func (r *Rules) Explain(path string) (Explanation, error) {
+	return r.ExplainPath(path, strings.HasSuffix(path, "/"))
+}
+
+func (r *Rules) ExplainPath(path string, isDir bool) (Explanation, error) {
+	explanation := Explanation{Index: -1}
+	t := newTarget(path, isDir)
+
+	// Only the git precedence checks the parent directories first, the other dialects match them by themselves.
+	start := 1
+	if r.dialect().Precedence() != PrecedenceGit {
+		start = len(t.segments)
+	}
+
+	for i := start; i <= len(t.segments); i++ {
+		last := i == len(t.segments)
+		level := t
+		if !last {
+			level = t.parent(i)
+		}
+
+		index := -1
+
+		for j, rule := range *r {
+			matched, err := rule.match(level)
+			if err != nil {
+				return Explanation{Index: -1}, err
+			}
+			if matched {
+				index = j
+				explanation.Matches = append(explanation.Matches, rule)
+			}
+		}
+
+		// A parent directory only decides when it is ignored, because its children cannot be re-included.
+		if index < 0 || (!last && (*r)[index].IsNegate) {
+			continue
+		}
+
+		explanation.Index = index
+		explanation.Pattern = &(*r)[index]
+		explanation.IsNegate = explanation.Pattern.IsNegate
+		explanation.Ignored = !explanation.IsNegate
+
+		if explanation.Ignored {
+			break
+		}
+	}
+
+	return explanation, nil
+}
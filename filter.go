@@ -0,0 +1,144 @@
+package goignore
+
+import (
+	"errors"
+	"io/fs"
+)
+
+type filterFS struct {
+	fsys  fs.FS
+	rules *Rules
+}
+
+func FilterFS(fsys fs.FS, rules *Rules) fs.FS {
+	return &filterFS{
+		fsys:  fsys,
+		rules: rules,
+	}
+}
+
+func (f *filterFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+
+	file, err := f.fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	if err := f.check("open", name, info.IsDir()); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	if info.IsDir() {
+		return &filterDir{File: file, fsys: f, name: name}, nil
+	}
+
+	return file, nil
+}
+
+func (f *filterFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrInvalid}
+	}
+
+	if err := f.check("readdir", name, true); err != nil {
+		return nil, err
+	}
+
+	entries, err := fs.ReadDir(f.fsys, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return f.filter("readdir", name, entries)
+}
+
+func (f *filterFS) Stat(name string) (fs.FileInfo, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrInvalid}
+	}
+
+	info, err := fs.Stat(f.fsys, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := f.check("stat", name, info.IsDir()); err != nil {
+		return nil, err
+	}
+
+	return info, nil
+}
+
+func (f *filterFS) check(op, name string, isDir bool) error {
+	if name == "." {
+		return nil
+	}
+
+	ignored, err := f.rules.MatchPath(name, isDir)
+	if err != nil {
+		return &fs.PathError{Op: op, Path: name, Err: err}
+	}
+	if ignored {
+		return &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+	}
+
+	return nil
+}
+
+func (f *filterFS) filter(op, dir string, entries []fs.DirEntry) ([]fs.DirEntry, error) {
+	filtered := entries[:0]
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if dir != "." {
+			name = dir + "/" + name
+		}
+
+		ignored, err := f.rules.MatchPath(name, entry.IsDir())
+		if err != nil {
+			return nil, &fs.PathError{Op: op, Path: name, Err: err}
+		}
+		if !ignored {
+			filtered = append(filtered, entry)
+		}
+	}
+
+	return filtered, nil
+}
+
+type filterDir struct {
+	fs.File
+	fsys *filterFS
+	name string
+}
+
+func (d *filterDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	dir, ok := d.File.(fs.ReadDirFile)
+	if !ok {
+		return nil, &fs.PathError{Op: "readdir", Path: d.name, Err: errors.New("not implemented")}
+	}
+
+	for {
+		entries, err := dir.ReadDir(n)
+
+		filtered, filterErr := d.fsys.filter("readdir", d.name, entries)
+		if filterErr != nil {
+			return nil, filterErr
+		}
+
+		// A batch can be filtered out entirely, but an empty result with a nil error is only allowed for n <= 0.
+		if len(filtered) > 0 || err != nil || n <= 0 {
+			return filtered, err
+		}
+	}
+}
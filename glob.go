@@ -0,0 +1,493 @@
+package goignore
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const doubleStar = "**"
+
+func splitSegments(path string) []string {
+	return strings.Split(path, "/")
+}
+
+// validatePattern returns the offset and error of a malformed pattern. Like git, a "**" which is not a whole segment is
+// a regular "*" when double stars are allowed.
+func validatePattern(pattern string, allowDoubleStar bool) (int, error) {
+	if index := strings.Index(pattern, doubleStar); index >= 0 && !allowDoubleStar {
+		return index, ErrDoubleStarSyntax
+	}
+
+	pattern = negateClasses(pattern)
+
+	if _, err := matchName(pattern, "test"); err != nil {
+		return badPatternOffset(pattern), err
+	}
+
+	return 0, nil
+}
+
+// negateClasses replaces the "!" which negates a character class in gitignore patterns with the "^" of path.Match. The
+// offsets of the pattern are kept.
+func negateClasses(pattern string) string {
+	if !strings.Contains(pattern, "[!") {
+		return pattern
+	}
+
+	b := []byte(pattern)
+	for i := 0; i < len(b); i++ {
+		switch b[i] {
+		case '\\':
+			i++
+		case '[':
+			if i+1 < len(b) && b[i+1] == '!' {
+				b[i+1] = '^'
+			}
+			if end := classEnd(string(b), i); end >= 0 {
+				i = end
+			}
+		}
+	}
+
+	return string(b)
+}
+
+// badPatternOffset returns the offset of the escape or character class that makes the pattern malformed.
+func badPatternOffset(pattern string) int {
+	for i := 0; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '\\':
+			if i+1 == len(pattern) {
+				return i
+			}
+			i++
+		case '[':
+			end := classEnd(pattern, i)
+			if end < 0 {
+				return i
+			}
+			if _, err := matchName(pattern[i:end+1], "test"); err != nil {
+				return i
+			}
+			i = end
+		}
+	}
+
+	return 0
+}
+
+// matchName matches the name with path.Match, so the result is the same on every OS. Its errors are ErrBadPattern, like
+// filepath.Match.
+func matchName(pattern, name string) (bool, error) {
+	matched, err := path.Match(pattern, name)
+	if errors.Is(err, path.ErrBadPattern) {
+		return false, ErrBadPattern
+	}
+
+	return matched, err
+}
+
+func classEnd(pattern string, start int) int {
+	i := start + 1
+	if i < len(pattern) && pattern[i] == '^' {
+		i++
+	}
+
+	for ; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '\\':
+			i++
+		case ']':
+			return i
+		}
+	}
+
+	return -1
+}
+
+// target is a slash-separated path prepared for matching, so it is cleaned and split once for all the patterns of the
+// Rules.
+type target struct {
+	path     string
+	segments []string
+	isDir    bool
+}
+
+func newTarget(name string, isDir bool) target {
+	t := target{path: strings.Trim(path.Clean(name), "/"), isDir: isDir}
+	if t.path == "." {
+		t.path = ""
+	}
+	if t.path != "" {
+		t.segments = splitSegments(t.path)
+	}
+
+	return t
+}
+
+// parent returns the directory of the first n segments.
+func (t target) parent(n int) target {
+	return target{path: t.path[:t.offset(n)-1], segments: t.segments[:n], isDir: true}
+}
+
+// child returns the path relative to the directory of the first n segments.
+func (t target) child(n int) target {
+	return target{path: t.path[t.offset(n):], segments: t.segments[n:], isDir: t.isDir}
+}
+
+func (t target) offset(n int) int {
+	offset := 0
+	for _, segment := range t.segments[:n] {
+		offset += len(segment) + 1
+	}
+
+	return offset
+}
+
+type segmentKind int
+
+const (
+	segmentLiteral segmentKind = iota
+	segmentPrefix
+	segmentSuffix
+	segmentAny
+	segmentDoubleStar
+	segmentGlob
+)
+
+// segment is a compiled path segment of a pattern. Most segments are literals, extensions like "*.go" or prefixes like
+// "foo*", which are matched without path.Match.
+type segment struct {
+	kind segmentKind
+	text string
+	fold bool
+	re   *regexp.Regexp
+}
+
+func compileSegment(pattern string, ignoreCase bool) segment {
+	switch pattern {
+	case doubleStar:
+		return segment{kind: segmentDoubleStar}
+	case "*":
+		return segment{kind: segmentAny}
+	}
+
+	if text, ok := unescapeLiteral(pattern); ok {
+		return segment{kind: segmentLiteral, text: text, fold: ignoreCase}
+	}
+	if strings.HasPrefix(pattern, "*") {
+		if text, ok := unescapeLiteral(pattern[1:]); ok {
+			return segment{kind: segmentSuffix, text: text, fold: ignoreCase}
+		}
+	}
+	if strings.HasSuffix(pattern, "*") {
+		if text, ok := unescapeLiteral(pattern[:len(pattern)-1]); ok {
+			return segment{kind: segmentPrefix, text: text, fold: ignoreCase}
+		}
+	}
+
+	s := segment{kind: segmentGlob, text: pattern, fold: ignoreCase}
+
+	// A malformed pattern is left to path.Match, which reports ErrBadPattern.
+	if ignoreCase {
+		s.re, _ = foldGlob(pattern)
+	}
+
+	return s
+}
+
+// unescapeLiteral returns the text matched by the pattern if it has no wildcard or character class.
+func unescapeLiteral(pattern string) (string, bool) {
+	if !strings.ContainsAny(pattern, `*?[\`) {
+		return pattern, true
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '*', '?', '[':
+			return "", false
+		case '\\':
+			if i+1 == len(pattern) {
+				return "", false
+			}
+			i++
+		}
+		b.WriteByte(pattern[i])
+	}
+
+	return b.String(), true
+}
+
+func (s segment) match(name string) (bool, error) {
+	switch s.kind {
+	case segmentLiteral:
+		if s.fold {
+			return strings.EqualFold(name, s.text), nil
+		}
+		return name == s.text, nil
+	case segmentPrefix:
+		if s.fold {
+			return hasPrefixFold(name, s.text), nil
+		}
+		return strings.HasPrefix(name, s.text), nil
+	case segmentSuffix:
+		if s.fold {
+			return hasSuffixFold(name, s.text), nil
+		}
+		return strings.HasSuffix(name, s.text), nil
+	case segmentAny, segmentDoubleStar:
+		return true, nil
+	}
+
+	if s.re != nil {
+		return s.re.MatchString(name), nil
+	}
+
+	return matchName(s.text, name)
+}
+
+// equalFoldRune returns true if the runes are equal under Unicode simple case folding, like strings.EqualFold.
+func equalFoldRune(a, b rune) bool {
+	if a == b {
+		return true
+	}
+
+	for f := unicode.SimpleFold(a); f != a; f = unicode.SimpleFold(f) {
+		if f == b {
+			return true
+		}
+	}
+
+	return false
+}
+
+func hasPrefixFold(s, prefix string) bool {
+	for _, r := range prefix {
+		c, size := utf8.DecodeRuneInString(s)
+		if size == 0 || !equalFoldRune(c, r) {
+			return false
+		}
+		s = s[size:]
+	}
+
+	return true
+}
+
+func hasSuffixFold(s, suffix string) bool {
+	for suffix != "" {
+		r, size := utf8.DecodeLastRuneInString(suffix)
+		c, n := utf8.DecodeLastRuneInString(s)
+		if n == 0 || !equalFoldRune(c, r) {
+			return false
+		}
+		suffix, s = suffix[:len(suffix)-size], s[:len(s)-n]
+	}
+
+	return true
+}
+
+// foldString maps every rune to the same member of its case folding orbit, so strings equal under case folding are
+// equal after folding.
+func foldString(s string) string {
+	return strings.Map(func(r rune) rune {
+		min := r
+		for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+			if f < min {
+				min = f
+			}
+		}
+
+		return unicode.ToLower(min)
+	}, s)
+}
+
+// foldGlob translates the path.Match pattern to a case-insensitive regexp. Character classes are folded too, so
+// "[a-z]" matches "Q".
+func foldGlob(pattern string) (*regexp.Regexp, error) {
+	var b strings.Builder
+	b.WriteString("(?i)^")
+
+	for i := 0; i < len(pattern); {
+		switch pattern[i] {
+		case '*':
+			b.WriteString("[^/]*")
+			i++
+		case '?':
+			b.WriteString("[^/]")
+			i++
+		case '[':
+			class, n, err := translateClass(pattern[i:])
+			if err != nil {
+				return nil, err
+			}
+			b.WriteString(class)
+			i += n
+		case '\\':
+			i++
+			if i == len(pattern) {
+				return nil, ErrBadPattern
+			}
+			fallthrough
+		default:
+			r, size := utf8.DecodeRuneInString(pattern[i:])
+			b.WriteString(regexp.QuoteMeta(string(r)))
+			i += size
+		}
+	}
+
+	b.WriteString("$")
+
+	return regexp.Compile(b.String())
+}
+
+// translateClass translates the character class at the start of the pattern to a regexp class, and returns its length.
+func translateClass(pattern string) (string, int, error) {
+	var b strings.Builder
+	b.WriteString("[")
+
+	i := 1
+	if i < len(pattern) && pattern[i] == '^' {
+		b.WriteString("^")
+		i++
+	}
+
+	for ranges := 0; ; ranges++ {
+		if i == len(pattern) {
+			return "", 0, ErrBadPattern
+		}
+		if pattern[i] == ']' && ranges > 0 {
+			break
+		}
+
+		lo, n, err := classChar(pattern[i:])
+		if err != nil {
+			return "", 0, err
+		}
+		i += n
+		fmt.Fprintf(&b, `\x{%x}`, lo)
+
+		if i < len(pattern) && pattern[i] == '-' {
+			hi, n, err := classChar(pattern[i+1:])
+			if err != nil {
+				return "", 0, err
+			}
+			i += n + 1
+			fmt.Fprintf(&b, `-\x{%x}`, hi)
+		}
+	}
+
+	b.WriteString("]")
+
+	return b.String(), i + 1, nil
+}
+
+func classChar(pattern string) (rune, int, error) {
+	if pattern == "" || pattern[0] == '-' || pattern[0] == ']' {
+		return 0, 0, ErrBadPattern
+	}
+
+	n := 0
+	if pattern[0] == '\\' {
+		n++
+		if n == len(pattern) {
+			return 0, 0, ErrBadPattern
+		}
+	}
+
+	r, size := utf8.DecodeRuneInString(pattern[n:])
+
+	return r, n + size, nil
+}
+
+// gitGlob is a gitignore pattern compiled at parse time.
+type gitGlob struct {
+	raw        string
+	isDir      bool
+	ignoreCase bool
+	dirOnly    bool
+	anchored   bool
+	base       segment
+	segments   []segment
+}
+
+func compileGit(raw string, isDir, ignoreCase bool) gitGlob {
+	g := gitGlob{
+		raw:        raw,
+		isDir:      isDir,
+		ignoreCase: ignoreCase,
+		dirOnly:    isDir || strings.HasSuffix(raw, "/"),
+	}
+
+	raw = negateClasses(strings.TrimSuffix(raw, "/"))
+	if strings.HasPrefix(raw, "/") {
+		g.anchored = true
+		raw = strings.TrimPrefix(raw, "/")
+	} else if strings.Contains(raw, "/") {
+		g.anchored = true
+	}
+
+	if !g.anchored {
+		g.base = compileSegment(raw, ignoreCase)
+		return g
+	}
+
+	for _, pattern := range splitSegments(raw) {
+		g.segments = append(g.segments, compileSegment(pattern, ignoreCase))
+	}
+
+	return g
+}
+
+func (g *gitGlob) match(t target) (bool, error) {
+	if (g.dirOnly && !t.isDir) || len(t.segments) == 0 {
+		return false, nil
+	}
+
+	// An unanchored pattern matches the base name.
+	if !g.anchored {
+		return g.base.match(t.segments[len(t.segments)-1])
+	}
+
+	return matchSegments(g.segments, t.segments)
+}
+
+func matchSegments(pattern []segment, path []string) (bool, error) {
+	for len(pattern) > 0 {
+		if pattern[0].kind == segmentDoubleStar {
+			rest := pattern[1:]
+
+			// A trailing "/**" matches everything inside, but not the directory itself.
+			if len(rest) == 0 {
+				return len(path) > 0, nil
+			}
+
+			for i := 0; i <= len(path); i++ {
+				matched, err := matchSegments(rest, path[i:])
+				if err != nil || matched {
+					return matched, err
+				}
+			}
+
+			return false, nil
+		}
+
+		if len(path) == 0 {
+			return false, nil
+		}
+
+		matched, err := pattern[0].match(path[0])
+		if err != nil || !matched {
+			return false, err
+		}
+
+		pattern, path = pattern[1:], path[1:]
+	}
+
+	return len(path) == 0, nil
+}
@@ -3,25 +3,99 @@ package goignore
 import (
 	"bufio"
 	"bytes"
+	"context"
+	"errors"
 	"io"
+	"io/fs"
 	"os"
 	"strings"
 )
 
+// ParseOptions are not stored in the returned Rules: their Dialect and IgnoreCase are the ones of their first pattern,
+// so Rules of different dialects should not be joined.
+type ParseOptions struct {
+	AllErrors  bool
+	Lenient    bool
+	Warnings   *ParseErrors
+	Dialect    Dialect
+	IgnoreCase bool
+}
+
 func Parse(content string) (*Rules, error) {
-	rules := Rules{}
+	return ParseWithOptions(content, ParseOptions{})
+}
+
+func ParseWithOptions(content string, options ParseOptions) (*Rules, error) {
+	p := parser{options: options}
 
-	for _, line := range strings.Split(content, "\n") {
-		if err := rules.ParseLine(line); err != nil {
+	for i, line := range strings.Split(content, "\n") {
+		if err := p.parseLine(line, "", i+1); err != nil {
 			return nil, err
 		}
 	}
 
-	return &rules, nil
+	return p.result()
+}
+
+func ParseWithDialect(content string, dialect Dialect) (*Rules, error) {
+	return ParseWithOptions(content, ParseOptions{Dialect: dialect})
 }
 
 func ParseFile(file io.Reader) (*Rules, error) {
-	rules := Rules{}
+	return ParseFileWithOptions(file, ParseOptions{})
+}
+
+func ParseFileWithOptions(file io.Reader, options ParseOptions) (*Rules, error) {
+	source := ""
+	if named, ok := file.(interface{ Name() string }); ok {
+		source = named.Name()
+	}
+
+	return parseFile(context.Background(), file, source, options)
+}
+
+// ParseFileContext is like ParseFileWithOptions, but stops with ctx.Err() when the context is cancelled. A read which
+// blocks is not interrupted.
+func ParseFileContext(ctx context.Context, file io.Reader, options ParseOptions) (*Rules, error) {
+	source := ""
+	if named, ok := file.(interface{ Name() string }); ok {
+		source = named.Name()
+	}
+
+	return parseFile(ctx, file, source, options)
+}
+
+func ParseFileFromPath(path string) (*Rules, error) {
+	return ParseFileFromPathWithOptions(path, ParseOptions{})
+}
+
+func ParseFileFromPathWithOptions(path string, options ParseOptions) (*Rules, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	return parseFile(context.Background(), file, path, options)
+}
+
+func ParseFS(fsys fs.FS, name string) (*Rules, error) {
+	file, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	return parseFile(context.Background(), file, name, ParseOptions{})
+}
+
+// contextCheckLines is how often the context is checked while parsing, checking every line would slow down parsing.
+const contextCheckLines = 256
+
+func parseFile(ctx context.Context, file io.Reader, source string, options ParseOptions) (*Rules, error) {
+	p := parser{options: options}
 
 	s := bufio.NewScanner(file)
 	currentLine := 0
@@ -34,23 +108,70 @@ func ParseFile(file io.Reader) (*Rules, error) {
 			scannedBytes = bytes.TrimPrefix(scannedBytes, utf8bom)
 		}
 
-		if err := rules.ParseLine(string(scannedBytes)); err != nil {
+		currentLine++
+
+		if currentLine%contextCheckLines == 1 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
+
+		if err := p.parseLine(string(scannedBytes), source, currentLine); err != nil {
 			return nil, err
 		}
+	}
 
-		currentLine++
+	if err := s.Err(); err != nil {
+		return nil, err
 	}
 
-	return &rules, s.Err()
+	return p.result()
 }
 
-func ParseFileFromPath(path string) (*Rules, error) {
-	file, err := os.Open(path)
-	if err != nil {
-		return nil, err
+type parser struct {
+	options ParseOptions
+	lines   LineParser
+	rules   Rules
+	errors  ParseErrors
+}
+
+func (p *parser) parseLine(line, source string, number int) error {
+	dialect := p.options.Dialect
+	if dialect == nil {
+		dialect = DialectGit
 	}
 
-	defer file.Close()
+	if p.lines == nil {
+		p.lines = dialect
+		if stateful, ok := dialect.(StatefulDialect); ok {
+			p.lines = stateful.NewLineParser()
+		}
+	}
+
+	err := p.rules.parseLine(line, source, number, dialect, p.lines, p.options.IgnoreCase)
+
+	var parseErr *ParseError
+	if !(p.options.AllErrors || p.options.Lenient) || !errors.As(err, &parseErr) {
+		return err
+	}
+
+	p.errors = append(p.errors, parseErr)
+	return nil
+}
+
+func (p *parser) result() (*Rules, error) {
+	if len(p.errors) > 0 && !p.options.Lenient {
+		return nil, p.errors
+	}
+
+	if p.rules == nil {
+		p.rules = Rules{}
+	}
+
+	// In lenient mode the invalid lines are skipped like git does, and only reported as warnings.
+	if p.options.Warnings != nil {
+		*p.options.Warnings = append(*p.options.Warnings, p.errors...)
+	}
 
-	return ParseFile(file)
+	return &p.rules, nil
 }
@@ -0,0 +1,234 @@
+package goignore
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+type hgSyntax int
+
+const (
+	hgRegexp hgSyntax = iota
+	hgGlob
+	hgRootGlob
+	hgUnsupported
+)
+
+// hgSyntaxes maps the names accepted by "syntax:" lines and line prefixes to their syntax.
+var hgSyntaxes = map[string]hgSyntax{
+	"re":         hgRegexp,
+	"regexp":     hgRegexp,
+	"relre":      hgRegexp,
+	"glob":       hgGlob,
+	"relglob":    hgGlob,
+	"rootglob":   hgRootGlob,
+	"include":    hgUnsupported,
+	"subinclude": hgUnsupported,
+}
+
+// hgComment matches a comment and the backslashes before it, which are kept when they escape each other.
+var hgComment = regexp.MustCompile(`((?:^|[^\\])(?:\\\\)*)#.*`)
+
+type hgDialect struct{}
+
+func (hgDialect) Name() string {
+	return "hg"
+}
+
+// ParseLine parses a line with the default regexp syntax. Use NewLineParser to honour "syntax:" lines.
+func (d hgDialect) ParseLine(line string) (*Pattern, error) {
+	return d.NewLineParser().ParseLine(line)
+}
+
+func (hgDialect) NewLineParser() LineParser {
+	return &hgParser{syntax: hgRegexp}
+}
+
+func (hgDialect) Match(pattern *Pattern, path string, isDir bool) (bool, error) {
+	re := pattern.regexp
+	if re == nil {
+		expr := pattern.Raw
+		if pattern.IgnoreCase {
+			expr = "(?i)" + expr
+		}
+
+		var err error
+		if re, err = regexp.Compile(expr); err != nil {
+			return false, fmt.Errorf("%w: %v", ErrBadPattern, err)
+		}
+	}
+
+	return matchHg(re, path), nil
+}
+
+func (hgDialect) Precedence() Precedence {
+	return PrecedenceLastMatch
+}
+
+type hgParser struct {
+	syntax hgSyntax
+}
+
+func (p *hgParser) ParseLine(line string) (*Pattern, error) {
+	rule := line
+	if strings.Contains(rule, "#") {
+		rule = hgComment.ReplaceAllString(rule, "$1")
+		rule = strings.ReplaceAll(rule, `\#`, "#")
+	}
+
+	rule = strings.TrimRight(rule, " \t")
+	if rule == "" {
+		return nil, nil
+	}
+
+	if name, ok := strings.CutPrefix(rule, "syntax:"); ok {
+		syntax, ok := hgSyntaxes[strings.TrimSpace(name)]
+		if !ok || syntax == hgUnsupported {
+			return nil, &ParseError{Column: 1, Text: line, Err: ErrUnsupportedSyntax}
+		}
+
+		p.syntax = syntax
+		return nil, nil
+	}
+
+	syntax := p.syntax
+	if name, expr, ok := strings.Cut(rule, ":"); ok {
+		if prefixed, ok := hgSyntaxes[name]; ok {
+			if prefixed == hgUnsupported {
+				return nil, &ParseError{Column: 1, Text: line, Err: ErrUnsupportedSyntax}
+			}
+
+			syntax, rule = prefixed, expr
+		}
+	}
+
+	var expr string
+	switch syntax {
+	case hgGlob:
+		expr = `^(?:|.*/)` + translateHgGlob(rule) + `(?:/|$)`
+	case hgRootGlob:
+		expr = `^` + translateHgGlob(rule) + `(?:/|$)`
+	default:
+		expr = rule
+	}
+
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		offset := strings.Index(line, rule)
+		if offset < 0 {
+			offset = 0
+		}
+
+		return nil, &ParseError{
+			Column: offset + 1,
+			Text:   line,
+			Err:    fmt.Errorf("%w: %v", ErrBadPattern, err),
+		}
+	}
+
+	return &Pattern{
+		Raw:    rule,
+		Text:   line,
+		regexp: re,
+	}, nil
+}
+
+// translateHgGlob translates the glob to a regexp like Mercurial, where "**" matches any number of characters
+// including the separator and "{a,b}" matches one of the alternatives.
+func translateHgGlob(glob string) string {
+	var b strings.Builder
+	groups := 0
+
+	for i := 0; i < len(glob); i++ {
+		switch glob[i] {
+		case '*':
+			if i+1 < len(glob) && glob[i+1] == '*' {
+				i++
+
+				if i+1 < len(glob) && glob[i+1] == '/' {
+					i++
+					b.WriteString("(?:.*/)?")
+				} else {
+					b.WriteString(".*")
+				}
+			} else {
+				b.WriteString("[^/]*")
+			}
+		case '?':
+			b.WriteString(".")
+		case '[':
+			end := i + 1
+			if end < len(glob) && (glob[end] == '!' || glob[end] == ']') {
+				end++
+			}
+			for end < len(glob) && glob[end] != ']' {
+				end++
+			}
+
+			if end >= len(glob) {
+				b.WriteString(`\[`)
+				continue
+			}
+
+			class := strings.ReplaceAll(glob[i+1:end], `\`, `\\`)
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			} else if strings.HasPrefix(class, "^") {
+				class = `\` + class
+			}
+
+			b.WriteString("[" + class + "]")
+			i = end
+		case '{':
+			groups++
+			b.WriteString("(?:")
+		case '}':
+			if groups == 0 {
+				b.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+				continue
+			}
+
+			groups--
+			b.WriteString(")")
+		case ',':
+			if groups == 0 {
+				b.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+				continue
+			}
+
+			b.WriteString("|")
+		case '\\':
+			if i+1 < len(glob) {
+				i++
+			}
+			fallthrough
+		default:
+			r, size := utf8.DecodeRuneInString(glob[i:])
+			b.WriteString(regexp.QuoteMeta(string(r)))
+			i += size - 1
+		}
+	}
+
+	return b.String()
+}
+
+// matchHg returns true if the regexp matches the path or one of its parent directories. Like Mercurial, regexps are
+// not anchored and may match any part of the path.
+func matchHg(re *regexp.Regexp, name string) bool {
+	name = path.Clean(strings.Trim(name, "/"))
+	if name == "." {
+		return false
+	}
+
+	segments := splitSegments(name)
+	for i := 1; i <= len(segments); i++ {
+		if re.MatchString(strings.Join(segments[:i], "/")) {
+			return true
+		}
+	}
+
+	return false
+}
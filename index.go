@@ -0,0 +1,209 @@
+package goignore
+
+import (
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Index is a snapshot of Rules which only evaluates the patterns that can match a path. Literal names like
+// "node_modules", extensions like "*.pyc", name prefixes like "tmp*" and anchored prefixes like "/build" are looked
+// up, while the other patterns are evaluated for every path. Patterns which ignore case are only looked up by name and
+// extension.
+type Index struct {
+	rules    Rules
+	literals map[string][]int
+	suffixes map[string][]int
+	folded   map[string][]int
+	names    *prefixNode
+	prefixes *prefixNode
+	others   []int
+}
+
+// prefixNode is a trie of the leading literal bytes of the name patterns, or of the leading literal segments of the
+// anchored patterns.
+type prefixNode struct {
+	children map[string]*prefixNode
+	patterns []int
+}
+
+// Index returns an Index of the patterns of the Rules. Patterns added to the Rules later are not part of the Index.
+func (r *Rules) Index() *Index {
+	index := &Index{
+		rules:    append(Rules(nil), *r...),
+		literals: map[string][]int{},
+		suffixes: map[string][]int{},
+		folded:   map[string][]int{},
+		names:    &prefixNode{},
+		prefixes: &prefixNode{},
+	}
+
+	for i := range index.rules {
+		index.add(i)
+	}
+
+	return index
+}
+
+func (idx *Index) add(i int) {
+	pattern := &idx.rules[i]
+	if _, ok := pattern.dialect.(gitDialect); pattern.dialect != nil && !ok {
+		idx.others = append(idx.others, i)
+		return
+	}
+
+	glob := pattern.compiled()
+	if glob.ignoreCase {
+		idx.addFolded(i, glob)
+		return
+	}
+
+	if !glob.anchored {
+		switch prefix := literalPrefix(glob.base); {
+		case glob.base.kind == segmentLiteral:
+			idx.literals[glob.base.text] = append(idx.literals[glob.base.text], i)
+		case glob.base.kind == segmentSuffix && path.Ext(glob.base.text) != "":
+			ext := path.Ext(glob.base.text)
+			idx.suffixes[ext] = append(idx.suffixes[ext], i)
+		case prefix != "":
+			node := idx.names
+			for j := 0; j < len(prefix); j++ {
+				node = node.child(prefix[j : j+1])
+			}
+			node.patterns = append(node.patterns, i)
+		default:
+			idx.others = append(idx.others, i)
+		}
+
+		return
+	}
+
+	node := idx.prefixes
+	for _, segment := range glob.segments {
+		if segment.kind != segmentLiteral {
+			break
+		}
+
+		node = node.child(segment.text)
+	}
+
+	node.patterns = append(node.patterns, i)
+}
+
+// addFolded adds a pattern which ignores case by its folded name or extension. The extension keys start with "*".
+func (idx *Index) addFolded(i int, glob gitGlob) {
+	switch {
+	case glob.anchored:
+		idx.others = append(idx.others, i)
+	case glob.base.kind == segmentLiteral:
+		key := foldString(glob.base.text)
+		idx.folded[key] = append(idx.folded[key], i)
+	case glob.base.kind == segmentSuffix && path.Ext(glob.base.text) != "":
+		key := "*" + foldString(path.Ext(glob.base.text))
+		idx.folded[key] = append(idx.folded[key], i)
+	default:
+		idx.others = append(idx.others, i)
+	}
+}
+
+func (n *prefixNode) child(key string) *prefixNode {
+	if n.children == nil {
+		n.children = map[string]*prefixNode{}
+	}
+	if n.children[key] == nil {
+		n.children[key] = &prefixNode{}
+	}
+
+	return n.children[key]
+}
+
+// collect appends the patterns of the nodes along the keys.
+func (n *prefixNode) collect(candidates []int, keys []string) []int {
+	for _, key := range keys {
+		candidates = append(candidates, n.patterns...)
+		if n = n.children[key]; n == nil {
+			return candidates
+		}
+	}
+
+	return append(candidates, n.patterns...)
+}
+
+// literalPrefix returns the text every name matched by the segment starts with.
+func literalPrefix(s segment) string {
+	switch s.kind {
+	case segmentPrefix:
+		return s.text
+	case segmentGlob:
+		if end := strings.IndexAny(s.text, `*?[\`); end >= 0 {
+			return s.text[:end]
+		}
+	}
+
+	return ""
+}
+
+func (idx *Index) Match(path string) (bool, error) {
+	return idx.MatchPath(path, strings.HasSuffix(path, "/"))
+}
+
+// MatchPath returns true if the given path is ignored, exactly like Rules.MatchPath.
+func (idx *Index) MatchPath(path string, isDir bool) (bool, error) {
+	return matchLast(idx.rules, newTarget(path, isDir), idx.last)
+}
+
+// MatchFilepath is like MatchPath, but accepts an OS-native path.
+func (idx *Index) MatchFilepath(path string, isDir bool) (bool, error) {
+	return idx.MatchPath(filepath.ToSlash(path), isDir)
+}
+
+// last evaluates the candidates from the last pattern down, so the precedence is the same as the Rules.
+func (idx *Index) last(t target) (int, error) {
+	candidates := idx.candidates(t)
+	sort.Ints(candidates)
+
+	for i := len(candidates) - 1; i >= 0; i-- {
+		matched, err := idx.rules[candidates[i]].match(t)
+		if err != nil {
+			return -1, err
+		}
+		if matched {
+			return candidates[i], nil
+		}
+	}
+
+	return -1, nil
+}
+
+func (idx *Index) candidates(t target) []int {
+	candidates := append([]int(nil), idx.others...)
+	if len(t.segments) == 0 {
+		return candidates
+	}
+
+	base := t.segments[len(t.segments)-1]
+	candidates = append(candidates, idx.literals[base]...)
+	if ext := path.Ext(base); ext != "" {
+		candidates = append(candidates, idx.suffixes[ext]...)
+	}
+
+	if len(idx.folded) > 0 {
+		folded := foldString(base)
+		candidates = append(candidates, idx.folded[folded]...)
+		if ext := path.Ext(folded); ext != "" {
+			candidates = append(candidates, idx.folded["*"+ext]...)
+		}
+	}
+
+	node := idx.names
+	for i := 0; i < len(base) && node != nil; i++ {
+		candidates = append(candidates, node.patterns...)
+		node = node.children[base[i:i+1]]
+	}
+	if node != nil {
+		candidates = append(candidates, node.patterns...)
+	}
+
+	return idx.prefixes.collect(candidates, t.segments)
+}
@@ -0,0 +1,106 @@
+package goignore
+
+import (
+	"context"
+	"strings"
+)
+
+// Matcher is an immutable, compiled snapshot of Rules. Unlike Rules, which are a mutable builder, a Matcher is safe
+// for concurrent use by multiple goroutines.
+type Matcher struct {
+	rules Rules
+	index *Index
+}
+
+// Compile validates and compiles the patterns of the Rules, including the ones written by hand, into a Matcher.
+// Changes to the Rules after Compile do not affect the Matcher.
+func Compile(rules *Rules) (*Matcher, error) {
+	compiled := append(Rules(nil), *rules...)
+
+	for i := range compiled {
+		pattern := &compiled[i]
+
+		doubleStar := true
+		if pattern.dialect != nil {
+			d, ok := pattern.dialect.(gitDialect)
+			if !ok {
+				continue
+			}
+			doubleStar = d.doubleStar
+		}
+
+		if offset, err := validatePattern(pattern.Raw, doubleStar); err != nil {
+			text := pattern.Text
+			if text == "" {
+				text = pattern.Raw
+			}
+
+			return nil, &ParseError{
+				Source: pattern.Source,
+				Line:   pattern.Line,
+				Column: offset + 1,
+				Text:   text,
+				Err:    err,
+			}
+		}
+
+		glob := pattern.compiled()
+		pattern.glob = &glob
+	}
+
+	return &Matcher{
+		rules: compiled,
+		index: compiled.Index(),
+	}, nil
+}
+
+func (m *Matcher) Match(path string) (bool, error) {
+	return m.index.Match(path)
+}
+
+func (m *Matcher) MatchPath(path string, isDir bool) (bool, error) {
+	return m.index.MatchPath(path, isDir)
+}
+
+func (m *Matcher) MatchFilepath(path string, isDir bool) (bool, error) {
+	return m.index.MatchFilepath(path, isDir)
+}
+
+// MatchPaths matches every path like Match, and stops with ctx.Err() when the context is cancelled.
+func (m *Matcher) MatchPaths(ctx context.Context, paths []string) ([]bool, error) {
+	matched := make([]bool, len(paths))
+
+	for i, path := range paths {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		ignored, err := m.Match(path)
+		if err != nil {
+			return nil, err
+		}
+		matched[i] = ignored
+	}
+
+	return matched, nil
+}
+
+func (m *Matcher) Explain(path string) (Explanation, error) {
+	return m.ExplainPath(path, strings.HasSuffix(path, "/"))
+}
+
+// ExplainPath is like Rules.ExplainPath. The patterns of the Explanation are copies, so the Matcher cannot be changed.
+func (m *Matcher) ExplainPath(path string, isDir bool) (Explanation, error) {
+	explanation, err := m.rules.ExplainPath(path, isDir)
+	if explanation.Pattern != nil {
+		pattern := *explanation.Pattern
+		explanation.Pattern = &pattern
+	}
+
+	return explanation, err
+}
+
+// Patterns returns a copy of the patterns of the Matcher.
+func (m *Matcher) Patterns() Rules {
+	return append(Rules{}, m.rules...)
+}
@@ -0,0 +1,262 @@
+package goignore
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"sync"
+)
+
+type WalkOptions struct {
+	Workers int
+}
+
+// WalkParallel is like Walk, but reads the directories in parallel. fn is called concurrently, and the entries of
+// different directories are visited in no particular order.
+func WalkParallel(ctx context.Context, root string, rules *Rules, options WalkOptions, fn fs.WalkDirFunc) error {
+	matcher, err := Compile(rules)
+	if err != nil {
+		return err
+	}
+
+	return walkParallel(ctx, osWalker(root), options, walkDirFunc(osRel(root), matcher, nil, fn))
+}
+
+func WalkParallelFS(ctx context.Context, fsys fs.FS, root string, rules *Rules, options WalkOptions, fn fs.WalkDirFunc) error {
+	matcher, err := Compile(rules)
+	if err != nil {
+		return err
+	}
+
+	return walkParallel(ctx, fsWalker(fsys, root), options, walkDirFunc(fsRel(root), matcher, nil, fn))
+}
+
+// WalkTreeParallel is like WalkTree, but reads the directories in parallel. The ignore file of a directory is always
+// loaded before its entries are matched.
+func WalkTreeParallel(ctx context.Context, root string, tree *Tree, options WalkOptions, fn fs.WalkDirFunc) error {
+	return walkParallel(ctx, osWalker(root), options, walkDirFunc(osRel(root), tree, func(dir string) error {
+		return tree.Load(root, dir)
+	}, fn))
+}
+
+func WalkTreeParallelFS(ctx context.Context, fsys fs.FS, root string, tree *Tree, options WalkOptions, fn fs.WalkDirFunc) error {
+	sub, err := fs.Sub(fsys, root)
+	if err != nil {
+		return err
+	}
+
+	return walkParallel(ctx, fsWalker(fsys, root), options, walkDirFunc(fsRel(root), tree, func(dir string) error {
+		return tree.LoadFS(sub, dir)
+	}, fn))
+}
+
+// walker reads a file tree like filepath.WalkDir or fs.WalkDir.
+type walker struct {
+	root    string
+	stat    func(name string) (fs.FileInfo, error)
+	readDir func(name string) ([]fs.DirEntry, error)
+	join    func(elem ...string) string
+}
+
+func osWalker(root string) walker {
+	return walker{root: root, stat: os.Lstat, readDir: os.ReadDir, join: filepath.Join}
+}
+
+func fsWalker(fsys fs.FS, root string) walker {
+	return walker{
+		root: root,
+		stat: func(name string) (fs.FileInfo, error) {
+			return fs.Stat(fsys, name)
+		},
+		readDir: func(name string) ([]fs.DirEntry, error) {
+			return fs.ReadDir(fsys, name)
+		},
+		join: path.Join,
+	}
+}
+
+// walkParallel calls fn like fs.WalkDir, but the directories are read by a bounded pool of workers. A directory is
+// queued after fn is called for it, so the entries of its parent are always visited first.
+func walkParallel(ctx context.Context, w walker, options WalkOptions, fn fs.WalkDirFunc) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	info, err := w.stat(w.root)
+	if err != nil {
+		err = fn(w.root, nil, err)
+	} else {
+		root := fs.FileInfoToDirEntry(info)
+		if err = fn(w.root, root, nil); err == nil && root.IsDir() {
+			err = newParallelWalk(ctx, w, fn).run(w.root, root, options.Workers)
+		}
+	}
+
+	if errors.Is(err, fs.SkipDir) || errors.Is(err, fs.SkipAll) {
+		return nil
+	}
+
+	return err
+}
+
+type parallelDir struct {
+	path string
+	d    fs.DirEntry
+}
+
+type parallelWalk struct {
+	ctx    context.Context
+	walker walker
+	fn     fs.WalkDirFunc
+
+	mu      sync.Mutex
+	cond    *sync.Cond
+	queue   []parallelDir
+	pending int
+	stopped bool
+	err     error
+}
+
+func newParallelWalk(ctx context.Context, w walker, fn fs.WalkDirFunc) *parallelWalk {
+	p := &parallelWalk{ctx: ctx, walker: w, fn: fn}
+	p.cond = sync.NewCond(&p.mu)
+
+	return p
+}
+
+func (p *parallelWalk) run(root string, d fs.DirEntry, workers int) error {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	finished := make(chan struct{})
+	defer close(finished)
+
+	// The waiting workers are woken up when the context is cancelled.
+	go func() {
+		select {
+		case <-p.ctx.Done():
+			p.stop(p.ctx.Err())
+		case <-finished:
+		}
+	}()
+
+	p.push(parallelDir{path: root, d: d})
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for {
+				dir, ok := p.pop()
+				if !ok {
+					return
+				}
+
+				p.visit(dir)
+				p.done()
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.err
+}
+
+func (p *parallelWalk) visit(dir parallelDir) {
+	entries, err := p.walker.readDir(dir.path)
+	if err != nil {
+		// Like fs.WalkDir, fn is called again for a directory which cannot be read.
+		if err = p.fn(dir.path, dir.d, err); err != nil {
+			if !errors.Is(err, fs.SkipDir) {
+				p.stop(err)
+			}
+			return
+		}
+	}
+
+	for _, entry := range entries {
+		if err := p.ctx.Err(); err != nil {
+			p.stop(err)
+			return
+		}
+
+		name := p.walker.join(dir.path, entry.Name())
+		if err := p.fn(name, entry, nil); err != nil {
+			if errors.Is(err, fs.SkipDir) {
+				// A skipped file skips the remaining entries of its directory.
+				if entry.IsDir() {
+					continue
+				}
+				return
+			}
+
+			p.stop(err)
+			return
+		}
+
+		if entry.IsDir() {
+			p.push(parallelDir{path: name, d: entry})
+		}
+	}
+}
+
+func (p *parallelWalk) push(dir parallelDir) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.pending++
+	p.queue = append(p.queue, dir)
+	p.cond.Signal()
+}
+
+func (p *parallelWalk) pop() (parallelDir, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for len(p.queue) == 0 && p.pending > 0 && !p.stopped {
+		p.cond.Wait()
+	}
+
+	if p.stopped || len(p.queue) == 0 {
+		return parallelDir{}, false
+	}
+
+	dir := p.queue[len(p.queue)-1]
+	p.queue = p.queue[:len(p.queue)-1]
+
+	return dir, true
+}
+
+func (p *parallelWalk) done() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.pending--
+	if p.pending == 0 {
+		p.cond.Broadcast()
+	}
+}
+
+// stop stops the walk with the first error. fs.SkipAll stops it without error.
+func (p *parallelWalk) stop(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.stopped && !errors.Is(err, fs.SkipAll) {
+		p.err = err
+	}
+
+	p.stopped = true
+	p.cond.Broadcast()
+}
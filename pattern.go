@@ -1,22 +1,59 @@
 package goignore
 
 import (
-	"path/filepath"
+	"regexp"
 	"strings"
 )
 
 type Pattern struct {
-	Raw      string
-	IsNegate bool
-	IsDir    bool
+	Raw        string
+	IsNegate   bool
+	IsDir      bool
+	IgnoreCase bool
+	Source     string
+	Line       int
+	Text       string
+	dialect    Dialect
+	regexp     *regexp.Regexp
+	glob       *gitGlob
+}
+
+func (p *Pattern) Dialect() Dialect {
+	if p.dialect == nil {
+		return DialectGit
+	}
+
+	return p.dialect
 }
 
 func (p *Pattern) Match(path string) (bool, error) {
-	if strings.HasPrefix(p.Raw, "/") {
-		return filepath.Match(p.Raw, strings.TrimPrefix(path, "/"))
+	return p.MatchPath(path, strings.HasSuffix(path, "/"))
+}
+
+func (p *Pattern) MatchPath(path string, isDir bool) (bool, error) {
+	return p.match(newTarget(path, isDir))
+}
+
+func (p *Pattern) match(t target) (bool, error) {
+	if _, ok := p.dialect.(gitDialect); p.dialect == nil || ok {
+		glob := p.compiled()
+		return glob.match(t)
 	}
-	if strings.Contains(p.Raw, "/") {
-		return filepath.Match(p.Raw, path)
+
+	return p.dialect.Match(p, t.path, t.isDir)
+}
+
+// compiled returns the glob compiled at parse time. Patterns written by hand, or changed after parsing, are compiled on
+// every call.
+func (p *Pattern) compiled() gitGlob {
+	if p.glob != nil && p.glob.raw == p.Raw && p.glob.isDir == p.IsDir && p.glob.ignoreCase == p.IgnoreCase {
+		return *p.glob
 	}
-	return filepath.Match(p.Raw, filepath.Base(path))
+
+	return compileGit(p.Raw, p.IsDir, p.IgnoreCase)
+}
+
+// foldRegexp returns the regexp of a dialect which ignores case.
+func foldRegexp(re *regexp.Regexp) *regexp.Regexp {
+	return regexp.MustCompile("(?i)" + re.String())
 }
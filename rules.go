@@ -7,27 +7,83 @@ import (
 
 type Rules []Pattern
 
+// ParseLine parses the line with the Dialect and IgnoreCase of the Rules, which are the ones of their first pattern.
+// Empty Rules, e.g. of a file with only comments, are case-sensitive gitignore Rules.
 func (r *Rules) ParseLine(line string) error {
-	rule := strings.TrimSpace(line)
+	return r.ParseLineWithDialect(line, r.dialect())
+}
 
-	if rule == "" {
-		return nil
+func (r *Rules) ParseLineWithDialect(line string, dialect Dialect) error {
+	return r.parseLine(line, "", 0, dialect, dialect, r.ignoreCase())
+}
+
+func (r *Rules) parseLine(line, source string, number int, dialect Dialect, lines LineParser, ignoreCase bool) error {
+	line = strings.TrimSuffix(line, "\r")
+
+	pattern, err := lines.ParseLine(line)
+	if err != nil {
+		parseErr, ok := err.(*ParseError)
+		if !ok {
+			parseErr = &ParseError{Column: 1, Text: line, Err: err}
+		}
+
+		parseErr.Source = source
+		parseErr.Line = number
+		return parseErr
 	}
 
-	if strings.HasPrefix(rule, "#") {
+	if pattern == nil {
 		return nil
 	}
 
-	if strings.Contains(rule, "**") {
-		return ErrDoubleStarSyntax
+	pattern.Source = source
+	pattern.Line = number
+	if pattern.Text == "" {
+		pattern.Text = line
 	}
 
-	if _, err := filepath.Match(rule, "test"); err != nil {
-		return err
+	// The git dialect is left nil, so parsed patterns are equal to the ones written by hand.
+	if dialect != DialectGit {
+		pattern.dialect = dialect
+	}
+
+	if ignoreCase {
+		pattern.IgnoreCase = true
+		if pattern.glob != nil {
+			glob := compileGit(pattern.Raw, pattern.IsDir, true)
+			pattern.glob = &glob
+		}
+		if pattern.regexp != nil {
+			pattern.regexp = foldRegexp(pattern.regexp)
+		}
+	}
+
+	*r = append(*r, *pattern)
+	return nil
+}
+
+func parseGitLine(line string, doubleStar bool) (*Pattern, error) {
+	rule := trimTrailingSpaces(line)
+
+	if rule == "" {
+		return nil, nil
+	}
+
+	if strings.HasPrefix(rule, "#") {
+		return nil, nil
+	}
+
+	if offset, err := validatePattern(rule, doubleStar); err != nil {
+		return nil, &ParseError{
+			Column: offset + 1,
+			Text:   line,
+			Err:    err,
+		}
 	}
 
 	pattern := Pattern{
-		Raw: rule,
+		Raw:  rule,
+		Text: line,
 	}
 
 	if strings.HasPrefix(rule, "!") {
@@ -39,20 +95,90 @@ func (r *Rules) ParseLine(line string) error {
 		pattern.IsDir = true
 	}
 
-	*r = append(*r, pattern)
-	return nil
+	glob := compileGit(pattern.Raw, pattern.IsDir, false)
+	pattern.glob = &glob
+
+	return &pattern, nil
+}
+
+func trimTrailingSpaces(line string) string {
+	end := len(line)
+	for end > 0 && line[end-1] == ' ' {
+		end--
+	}
+
+	if end == len(line) {
+		return line
+	}
+
+	// The first trailing space is kept if it is escaped by an odd number of backslashes.
+	backslashes := 0
+	for i := end - 1; i >= 0 && line[i] == '\\'; i-- {
+		backslashes++
+	}
+	if backslashes%2 == 1 {
+		end++
+	}
+
+	return line[:end]
+}
+
+// ignoreCase returns true if the Rules are parsed to ignore case, which is decided by their first pattern. The option
+// is lost for Rules without patterns.
+func (r *Rules) ignoreCase() bool {
+	return len(*r) > 0 && (*r)[0].IgnoreCase
 }
 
 func (r *Rules) Match(path string) (bool, error) {
-	for _, rule := range *r {
-		matched, err := rule.Match(path)
-		if err != nil {
+	return r.MatchPath(path, strings.HasSuffix(path, "/"))
+}
+
+func (r *Rules) MatchPath(path string, isDir bool) (bool, error) {
+	return matchLast(*r, newTarget(path, isDir), r.last)
+}
+
+// MatchFilepath is like MatchPath, but accepts an OS-native path, e.g. `foo\bar` on Windows.
+func (r *Rules) MatchFilepath(path string, isDir bool) (bool, error) {
+	return r.MatchPath(filepath.ToSlash(path), isDir)
+}
+
+// matchLast matches the path with the precedence of the dialect of the rules. last returns the index of the last
+// pattern matching a path, or -1.
+func matchLast(rules Rules, t target, last func(target) (int, error)) (bool, error) {
+	match := func(t target) (bool, error) {
+		index, err := last(t)
+		if err != nil || index < 0 {
 			return false, err
 		}
+
+		return !rules[index].IsNegate, nil
+	}
+
+	if rules.dialect().Precedence() != PrecedenceGit {
+		return match(t)
+	}
+
+	// Like git, a path inside an ignored directory is ignored and cannot be re-included.
+	for i := 1; i < len(t.segments); i++ {
+		ignored, err := match(t.parent(i))
+		if err != nil || ignored {
+			return ignored, err
+		}
+	}
+
+	return match(t)
+}
+
+func (r *Rules) last(t target) (int, error) {
+	for i := len(*r) - 1; i >= 0; i-- {
+		matched, err := (*r)[i].match(t)
+		if err != nil {
+			return -1, err
+		}
 		if matched {
-			return !rule.IsNegate, nil
+			return i, nil
 		}
 	}
 
-	return false, nil
+	return -1, nil
 }
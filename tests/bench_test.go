@@ -0,0 +1,112 @@
+package tests
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/dev-addict/goignore"
+)
+
+// benchmarkContent returns an ignore file with the usual mix of literal names, extensions, prefixes and anchored
+// patterns.
+func benchmarkContent(n int) string {
+	var b strings.Builder
+	for i := 0; i < n; i++ {
+		switch i % 6 {
+		case 0:
+			fmt.Fprintf(&b, "name%d\n", i)
+		case 1:
+			fmt.Fprintf(&b, "*.ext%d\n", i)
+		case 2:
+			fmt.Fprintf(&b, "/build%d/\n", i)
+		case 3:
+			fmt.Fprintf(&b, "tmp%d*\n", i)
+		case 4:
+			fmt.Fprintf(&b, "src/**/gen%d\n", i)
+		case 5:
+			fmt.Fprintf(&b, "!keep%d.[ch]\n", i)
+		}
+	}
+
+	return b.String()
+}
+
+func benchmarkPaths(n int) []string {
+	paths := make([]string, n)
+	for i := range paths {
+		paths[i] = fmt.Sprintf("src/pkg%d/internal/file%d.go", i%10, i)
+	}
+
+	return paths
+}
+
+func benchmarkRules(b *testing.B, patterns int) *goignore.Rules {
+	rules, err := goignore.Parse(benchmarkContent(patterns))
+	if err != nil {
+		b.Fatalf("Unexpected error: %s", err.Error())
+	}
+
+	return rules
+}
+
+// handWritten drops the compiled form of the patterns, so they are interpreted on every match.
+func handWritten(rules *goignore.Rules) *goignore.Rules {
+	written := goignore.Rules{}
+	for _, rule := range *rules {
+		written = append(written, goignore.Pattern{Raw: rule.Raw, IsNegate: rule.IsNegate, IsDir: rule.IsDir})
+	}
+
+	return &written
+}
+
+func BenchmarkRulesMatch(b *testing.B) {
+	paths := benchmarkPaths(1000)
+
+	for _, patterns := range []int{10, 100, 1000} {
+		rules := benchmarkRules(b, patterns)
+
+		b.Run(fmt.Sprintf("compiled/%d", patterns), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := rules.Match(paths[i%len(paths)]); err != nil {
+					b.Fatalf("Unexpected error: %s", err.Error())
+				}
+			}
+		})
+
+		written := handWritten(rules)
+		b.Run(fmt.Sprintf("interpreted/%d", patterns), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := written.Match(paths[i%len(paths)]); err != nil {
+					b.Fatalf("Unexpected error: %s", err.Error())
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkIndexMatch(b *testing.B) {
+	paths := benchmarkPaths(1000)
+
+	for _, patterns := range []int{10, 100, 1000, 10000} {
+		index := benchmarkRules(b, patterns).Index()
+
+		b.Run(fmt.Sprintf("%d", patterns), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := index.Match(paths[i%len(paths)]); err != nil {
+					b.Fatalf("Unexpected error: %s", err.Error())
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkParse(b *testing.B) {
+	content := benchmarkContent(1000)
+
+	for i := 0; i < b.N; i++ {
+		if _, err := goignore.Parse(content); err != nil {
+			b.Fatalf("Unexpected error: %s", err.Error())
+		}
+	}
+}
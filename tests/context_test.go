@@ -0,0 +1,174 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/dev-addict/goignore"
+)
+
+// cancellingReader returns the lines of an endless ignore file, and cancels the context after the given number of
+// reads.
+type cancellingReader struct {
+	reads  int
+	cancel context.CancelFunc
+}
+
+func (r *cancellingReader) Read(p []byte) (int, error) {
+	r.reads--
+	if r.reads == 0 {
+		r.cancel()
+	}
+
+	return copy(p, strings.Repeat("*.log\n", len(p)/6)), nil
+}
+
+func TestParseFileContext(t *testing.T) {
+	t.Run("should parse like ParseFileWithOptions", func(t *testing.T) {
+		var warnings goignore.ParseErrors
+
+		parsedRules, err := goignore.ParseFileContext(context.Background(), strings.NewReader("foo\n[123\nbar"), goignore.ParseOptions{Lenient: true, Warnings: &warnings})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err.Error())
+		}
+
+		if len(warnings) != 1 {
+			t.Errorf("Expected 1 warning, got %v", warnings)
+		}
+
+		expected := goignore.Rules{
+			{Raw: "foo", Line: 1, Text: "foo"},
+			{Raw: "bar", Line: 3, Text: "bar"},
+		}
+		if parsedRules == nil || !equalRules(expected, *parsedRules) {
+			t.Errorf("Content invalidly parsed")
+		}
+	})
+
+	t.Run("should stop when the context is cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		parsedRules, err := goignore.ParseFileContext(ctx, &cancellingReader{reads: 10, cancel: cancel}, goignore.ParseOptions{})
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected error %s, got %v", context.Canceled.Error(), err)
+		}
+		if parsedRules != nil {
+			t.Errorf("Rules should be nil")
+		}
+	})
+
+	t.Run("should not parse with cancelled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := goignore.ParseFileContext(ctx, strings.NewReader("foo"), goignore.ParseOptions{})
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected error %s, got %v", context.Canceled.Error(), err)
+		}
+	})
+}
+
+func TestWalkContext(t *testing.T) {
+	fsys := fstest.MapFS{
+		"root/.gitignore":   {Data: []byte("*.log\n")},
+		"root/a/main.go":    {},
+		"root/b/main.go":    {},
+		"root/c/debug.log":  {},
+		"root/d/e/f/doc.md": {},
+	}
+
+	rules, err := goignore.Parse("*.log")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+
+	// cancelAfter returns an fs.WalkDirFunc which cancels the context after visiting the given number of entries.
+	cancelAfter := func(n int, cancel context.CancelFunc, visited *int) fs.WalkDirFunc {
+		return func(path string, d fs.DirEntry, err error) error {
+			*visited++
+			if *visited == n {
+				cancel()
+			}
+			return err
+		}
+	}
+
+	walks := map[string]func(ctx context.Context, fn fs.WalkDirFunc) error{
+		"WalkContext": func(ctx context.Context, fn fs.WalkDirFunc) error {
+			return goignore.WalkContext(ctx, createTree(t, "a/main.go", "b/main.go", "c/d/e.go"), rules, fn)
+		},
+		"WalkFSContext": func(ctx context.Context, fn fs.WalkDirFunc) error {
+			return goignore.WalkFSContext(ctx, fsys, "root", rules, fn)
+		},
+		"WalkTreeContext": func(ctx context.Context, fn fs.WalkDirFunc) error {
+			return goignore.WalkTreeContext(ctx, createTree(t, "a/main.go", "b/main.go", "c/d/e.go"), goignore.NewTree(".gitignore"), fn)
+		},
+		"WalkTreeFSContext": func(ctx context.Context, fn fs.WalkDirFunc) error {
+			return goignore.WalkTreeFSContext(ctx, fsys, "root", goignore.NewTree(".gitignore"), fn)
+		},
+	}
+
+	for name, walk := range walks {
+		t.Run(name+" should stop when the context is cancelled", func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			visited := 0
+			err := walk(ctx, cancelAfter(2, cancel, &visited))
+			if !errors.Is(err, context.Canceled) {
+				t.Errorf("Expected error %s, got %v", context.Canceled.Error(), err)
+			}
+			if visited != 2 {
+				t.Errorf("Should visit 2 entries, got %d", visited)
+			}
+		})
+
+		t.Run(name+" should walk without cancellation", func(t *testing.T) {
+			visited := 0
+			if err := walk(context.Background(), cancelAfter(-1, nil, &visited)); err != nil {
+				t.Errorf("Unexpected error: %s", err.Error())
+			}
+			if visited < 4 {
+				t.Errorf("Should visit every entry, got %d", visited)
+			}
+		})
+	}
+}
+
+func TestMatcherMatchPaths(t *testing.T) {
+	matcher, err := goignore.Compile(&goignore.Rules{{Raw: "*.log"}, {Raw: "build/", IsDir: true}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+
+	t.Run("should match every path", func(t *testing.T) {
+		matched, err := matcher.MatchPaths(context.Background(), []string{"main.go", "debug.log", "build/", "build"})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err.Error())
+		}
+
+		expected := []bool{false, true, true, false}
+		if !reflect.DeepEqual(expected, matched) {
+			t.Errorf("Expected %v, got %v", expected, matched)
+		}
+	})
+
+	t.Run("should stop when the context is cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		matched, err := matcher.MatchPaths(ctx, []string{"main.go"})
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected error %s, got %v", context.Canceled.Error(), err)
+		}
+		if matched != nil {
+			t.Errorf("Result should be nil")
+		}
+	})
+}
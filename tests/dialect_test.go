@@ -0,0 +1,161 @@
+package tests
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/dev-addict/goignore"
+)
+
+type suffixDialect struct{}
+
+func (suffixDialect) Name() string {
+	return "suffix"
+}
+
+func (suffixDialect) ParseLine(line string) (*goignore.Pattern, error) {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return nil, nil
+	}
+
+	if strings.ContainsAny(line, "*?[") {
+		return nil, goignore.ErrBadPattern
+	}
+
+	return &goignore.Pattern{Raw: line}, nil
+}
+
+func (suffixDialect) Match(pattern *goignore.Pattern, path string, isDir bool) (bool, error) {
+	return strings.HasSuffix(path, pattern.Raw), nil
+}
+
+func (suffixDialect) Precedence() goignore.Precedence {
+	return goignore.PrecedenceLastMatch
+}
+
+func TestDialect(t *testing.T) {
+	t.Run("should look up built-in dialects", func(t *testing.T) {
+		cases := map[string]goignore.Dialect{
+			"git":             goignore.DialectGit,
+			".gitignore":      goignore.DialectGit,
+			"docker":          goignore.DialectDocker,
+			".dockerignore":   goignore.DialectDocker,
+			".npmignore":      goignore.DialectNpm,
+			".eslintignore":   goignore.DialectESLint,
+			".prettierignore": goignore.DialectPrettier,
+			".helmignore":     goignore.DialectHelm,
+			".gcloudignore":   goignore.DialectGcloud,
+			".hgignore":       goignore.DialectHg,
+		}
+
+		for name, expected := range cases {
+			dialect, ok := goignore.LookupDialect(name)
+			if !ok {
+				t.Errorf("Should find dialect %s", name)
+			} else if dialect != expected {
+				t.Errorf("Expected dialect %s for %s, got %s", expected.Name(), name, dialect.Name())
+			}
+		}
+
+		if _, ok := goignore.LookupDialect(".fooignore"); ok {
+			t.Errorf("Should not find dialect .fooignore")
+		}
+	})
+
+	t.Run("should register dialect", func(t *testing.T) {
+		goignore.RegisterDialect(suffixDialect{})
+
+		dialect, ok := goignore.LookupDialect(".suffixignore")
+		if !ok {
+			t.Fatalf("Should find dialect .suffixignore")
+		}
+
+		rules, err := goignore.ParseWithDialect("_test.go\n\n.go", dialect)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err.Error())
+		}
+
+		if len(*rules) != 2 || (*rules)[1].Line != 3 || (*rules)[1].Dialect() != dialect {
+			t.Errorf("Content invalidly parsed")
+		}
+
+		matched, err := rules.Match("foo/main.go")
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err.Error())
+		} else if !matched {
+			t.Errorf("Should match foo/main.go")
+		}
+
+		matched, err = rules.Match("foo/main.c")
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err.Error())
+		} else if matched {
+			t.Errorf("Should not match foo/main.c")
+		}
+
+		_, err = goignore.ParseWithDialect("foo\n*.go", dialect)
+
+		var parseErr *goignore.ParseError
+		if !errors.As(err, &parseErr) {
+			t.Fatalf("Expected parse error, got %v", err)
+		}
+		if parseErr.Line != 2 || !errors.Is(err, goignore.ErrBadPattern) {
+			t.Errorf("Unexpected error: %s", err.Error())
+		}
+	})
+
+	t.Run("should parse line with dialect of rules", func(t *testing.T) {
+		rules, err := goignore.ParseWithDialect("vendor", goignore.DialectDocker)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err.Error())
+		}
+
+		if err := rules.ParseLine("  !vendor/keep.go  "); err != nil {
+			t.Fatalf("Unexpected error: %s", err.Error())
+		}
+
+		matched, err := rules.Match("vendor/keep.go")
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err.Error())
+		} else if matched {
+			t.Errorf("Should not match vendor/keep.go")
+		}
+	})
+
+	t.Run("should parse line with dialect", func(t *testing.T) {
+		rules := goignore.Rules{}
+
+		if err := rules.ParseLineWithDialect("./foo", goignore.DialectDocker); err != nil {
+			t.Fatalf("Unexpected error: %s", err.Error())
+		}
+
+		if rules[0].Raw != "foo" || rules[0].Dialect() != goignore.DialectDocker {
+			t.Errorf("Rule should be docker foo")
+		}
+	})
+
+	t.Run("should not parse double star syntax with helm", func(t *testing.T) {
+		_, err := goignore.ParseWithDialect("foo\n**/bar", goignore.DialectHelm)
+		if !errors.Is(err, goignore.ErrDoubleStarSyntax) {
+			t.Errorf("Expected error %s, got %v", goignore.ErrDoubleStarSyntax.Error(), err)
+		}
+	})
+
+	t.Run("should match gitignore syntax with npm", func(t *testing.T) {
+		rules, err := goignore.ParseWithDialect("*.log\n!keep.log\ntest/", goignore.DialectNpm)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err.Error())
+		}
+
+		for path, expected := range map[string]bool{"debug.log": true, "keep.log": false, "test/index.js": true, "index.js": false} {
+			matched, err := rules.Match(path)
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err.Error())
+			} else if matched != expected {
+				t.Errorf("Match of %s should be %t", path, expected)
+			}
+		}
+	})
+}
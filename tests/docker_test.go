@@ -0,0 +1,145 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dev-addict/goignore"
+)
+
+func TestDialectDocker(t *testing.T) {
+	t.Run("should match like docker", func(t *testing.T) {
+		cases := []struct {
+			content string
+			ignored []string
+			kept    []string
+		}{
+			{"*/temp*", []string{"foo/temporary.txt", "foo/temp/bar"}, []string{"temp", "foo/bar/temp"}},
+			{"*/*/temp*", []string{"foo/bar/temporary.txt"}, []string{"foo/temp", "temp"}},
+			{"temp?", []string{"tempa", "tempb/foo"}, []string{"temp", "foo/tempa"}},
+			{"**/*.go", []string{"main.go", "foo/bar/main.go"}, []string{"main.c"}},
+			{"foo**bar", []string{"foobar", "foo/baz/bar", "foo/bar/baz"}, []string{"foo/baz"}},
+			{"*.md\n!README*.md\nREADME-secret.md", []string{"CHANGELOG.md", "README-secret.md"}, []string{"README.md"}},
+			{"vendor\n!vendor/keep.go", []string{"vendor", "vendor/a.go", "vendor/a/b.go"}, []string{"vendor/keep.go"}},
+			{"/foo/", []string{"foo", "foo/bar"}, []string{"bar/foo"}},
+			{"./foo/../bar", []string{"bar", "bar/baz"}, []string{"foo"}},
+			{"  foo  \n# comment\n  ! bar", []string{"foo"}, []string{"bar", "#", "comment"}},
+			{"é.txt", []string{"é.txt", "é.txt/foo"}, []string{"e.txt", "é_txt"}},
+			{"[é-ë]?", []string{"ê€"}, []string{"e€", "ê/"}},
+			{"[\\d]", []string{"d"}, []string{"1"}},
+			{"[\\w-z]", []string{"x"}, []string{"a"}},
+		}
+
+		for _, c := range cases {
+			rules, err := goignore.ParseWithDialect(c.content, goignore.DialectDocker)
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err.Error())
+				continue
+			}
+
+			for _, path := range c.ignored {
+				matched, err := rules.Match(path)
+				if err != nil {
+					t.Errorf("Unexpected error: %s", err.Error())
+				} else if !matched {
+					t.Errorf("%q should match %s", c.content, path)
+				}
+			}
+
+			for _, path := range c.kept {
+				matched, err := rules.Match(path)
+				if err != nil {
+					t.Errorf("Unexpected error: %s", err.Error())
+				} else if matched {
+					t.Errorf("%q should not match %s", c.content, path)
+				}
+			}
+		}
+	})
+
+	t.Run("should clean paths", func(t *testing.T) {
+		rules, err := goignore.ParseWithDialect("foo/bar", goignore.DialectDocker)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err.Error())
+		}
+
+		for _, path := range []string{"./foo/bar", "foo//bar/", "foo/baz/../bar"} {
+			matched, err := rules.Match(path)
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err.Error())
+			} else if !matched {
+				t.Errorf("Should match %s", path)
+			}
+		}
+	})
+
+	t.Run("should explain re-included path", func(t *testing.T) {
+		rules, err := goignore.ParseWithDialect("vendor\n!vendor/keep.go", goignore.DialectDocker)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err.Error())
+		}
+
+		explanation, err := rules.Explain("vendor/keep.go")
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err.Error())
+		}
+
+		if explanation.Ignored {
+			t.Errorf("Should not be ignored")
+		}
+		if explanation.Index != 1 {
+			t.Errorf("Index should be 1, got %d", explanation.Index)
+		}
+		if len(explanation.Matches) != 2 {
+			t.Errorf("Matches should have 2 items, got %d", len(explanation.Matches))
+		}
+	})
+
+	t.Run("should not parse invalid pattern", func(t *testing.T) {
+		_, err := goignore.ParseWithDialect("foo\n  [123", goignore.DialectDocker)
+
+		var parseErr *goignore.ParseError
+		if !errors.As(err, &parseErr) {
+			t.Fatalf("Expected parse error, got %v", err)
+		}
+
+		if !errors.Is(err, goignore.ErrBadPattern) {
+			t.Errorf("Expected error %s, got %s", goignore.ErrBadPattern.Error(), err.Error())
+		}
+		if parseErr.Line != 2 || parseErr.Column != 3 {
+			t.Errorf("Unexpected position %d:%d", parseErr.Line, parseErr.Column)
+		}
+	})
+
+	t.Run("should not parse exclusion without pattern", func(t *testing.T) {
+		_, err := goignore.ParseWithDialect("foo\n  ! ", goignore.DialectDocker)
+
+		var parseErr *goignore.ParseError
+		if !errors.As(err, &parseErr) {
+			t.Fatalf("Expected parse error, got %v", err)
+		}
+
+		if !errors.Is(err, goignore.ErrBadPattern) {
+			t.Errorf("Expected error %s, got %s", goignore.ErrBadPattern.Error(), err.Error())
+		}
+		if parseErr.Line != 2 || parseErr.Column != 3 {
+			t.Errorf("Unexpected position %d:%d", parseErr.Line, parseErr.Column)
+		}
+	})
+
+	t.Run("should not parse reversed range", func(t *testing.T) {
+		_, err := goignore.ParseWithDialect("  [z-a]", goignore.DialectDocker)
+
+		var parseErr *goignore.ParseError
+		if !errors.As(err, &parseErr) {
+			t.Fatalf("Expected parse error, got %v", err)
+		}
+
+		if !errors.Is(err, goignore.ErrBadPattern) {
+			t.Errorf("Expected error %s, got %s", goignore.ErrBadPattern.Error(), err.Error())
+		}
+		if parseErr.Line != 1 || parseErr.Column != 3 {
+			t.Errorf("Unexpected position %d:%d", parseErr.Line, parseErr.Column)
+		}
+	})
+}
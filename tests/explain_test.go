@@ -0,0 +1,137 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/dev-addict/goignore"
+)
+
+func TestExplain(t *testing.T) {
+	rules, err := goignore.Parse("*.log\n!important.log\nvendor/\n!vendor/keep.go\nbuild/\n!/build/\n")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+
+	t.Run("should explain not matched path", func(t *testing.T) {
+		explanation, err := rules.Explain("main.go")
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err.Error())
+		}
+
+		if explanation.Ignored {
+			t.Errorf("Should not be ignored")
+		}
+		if explanation.Pattern != nil {
+			t.Errorf("Pattern should be nil")
+		}
+		if explanation.Index != -1 {
+			t.Errorf("Index should be -1, got %d", explanation.Index)
+		}
+		if len(explanation.Matches) != 0 {
+			t.Errorf("Matches should be empty, got %d", len(explanation.Matches))
+		}
+	})
+
+	t.Run("should explain ignored path", func(t *testing.T) {
+		explanation, err := rules.Explain("debug.log")
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err.Error())
+		}
+
+		if !explanation.Ignored {
+			t.Errorf("Should be ignored")
+		}
+		if explanation.Pattern == nil || explanation.Pattern.Raw != "*.log" || explanation.Pattern.Line != 1 {
+			t.Errorf("Pattern should be *.log at line 1")
+		}
+		if explanation.Index != 0 {
+			t.Errorf("Index should be 0, got %d", explanation.Index)
+		}
+		if explanation.IsNegate {
+			t.Errorf("Should not be negate")
+		}
+		if len(explanation.Matches) != 1 {
+			t.Errorf("Matches should have 1 item, got %d", len(explanation.Matches))
+		}
+	})
+
+	t.Run("should explain re-included path", func(t *testing.T) {
+		explanation, err := rules.Explain("important.log")
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err.Error())
+		}
+
+		if explanation.Ignored {
+			t.Errorf("Should not be ignored")
+		}
+		if explanation.Pattern == nil || explanation.Pattern.Raw != "important.log" {
+			t.Errorf("Pattern should be important.log")
+		}
+		if explanation.Index != 1 {
+			t.Errorf("Index should be 1, got %d", explanation.Index)
+		}
+		if !explanation.IsNegate {
+			t.Errorf("Should be negate")
+		}
+		if len(explanation.Matches) != 2 {
+			t.Errorf("Matches should have 2 items, got %d", len(explanation.Matches))
+		}
+	})
+
+	t.Run("should explain path inside ignored directory", func(t *testing.T) {
+		explanation, err := rules.Explain("vendor/keep.go")
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err.Error())
+		}
+
+		if !explanation.Ignored {
+			t.Errorf("Should be ignored")
+		}
+		if explanation.Pattern == nil || explanation.Pattern.Raw != "vendor/" {
+			t.Errorf("Pattern should be vendor/")
+		}
+		if explanation.Index != 2 {
+			t.Errorf("Index should be 2, got %d", explanation.Index)
+		}
+		if len(explanation.Matches) != 1 {
+			t.Errorf("Matches should have 1 item, got %d", len(explanation.Matches))
+		}
+	})
+
+	t.Run("should explain path inside re-included directory", func(t *testing.T) {
+		explanation, err := rules.ExplainPath("build/debug.log", false)
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err.Error())
+		}
+
+		if !explanation.Ignored {
+			t.Errorf("Should be ignored")
+		}
+		if explanation.Pattern == nil || explanation.Pattern.Raw != "*.log" {
+			t.Errorf("Pattern should be *.log")
+		}
+		if len(explanation.Matches) != 3 {
+			t.Errorf("Matches should have 3 items, got %d", len(explanation.Matches))
+		}
+	})
+
+	t.Run("should agree with MatchPath", func(t *testing.T) {
+		for _, path := range []string{"main.go", "debug.log", "important.log", "vendor", "vendor/keep.go", "build", "build/main", "src/build/main"} {
+			for _, isDir := range []bool{false, true} {
+				explanation, err := rules.ExplainPath(path, isDir)
+				if err != nil {
+					t.Errorf("Unexpected error: %s", err.Error())
+				}
+
+				matched, err := rules.MatchPath(path, isDir)
+				if err != nil {
+					t.Errorf("Unexpected error: %s", err.Error())
+				}
+
+				if explanation.Ignored != matched {
+					t.Errorf("Explanation of %s should be %t", path, matched)
+				}
+			}
+		}
+	})
+}
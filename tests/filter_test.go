@@ -0,0 +1,127 @@
+package tests
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	"github.com/dev-addict/goignore"
+)
+
+func TestFilterFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"main.go":          {Data: []byte("package main")},
+		"debug.log":        {},
+		"build/main":       {},
+		"src/app.go":       {},
+		"src/app.log":      {},
+		"src/build/app":    {},
+		"vendor/a/b.go":    {},
+		"assets/build":     {},
+		"assets/style.css": {},
+	}
+
+	rules, err := goignore.Parse("*.log\nbuild/\n/vendor")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+
+	filtered := goignore.FilterFS(fsys, rules)
+
+	t.Run("should pass fs tests", func(t *testing.T) {
+		if err := fstest.TestFS(filtered, "main.go", "src/app.go", "assets/build", "assets/style.css"); err != nil {
+			t.Errorf("Unexpected error: %s", err.Error())
+		}
+	})
+
+	t.Run("should not open ignored paths", func(t *testing.T) {
+		for _, name := range []string{"debug.log", "build", "build/main", "src/app.log", "vendor/a/b.go"} {
+			_, err := filtered.Open(name)
+			if !errors.Is(err, fs.ErrNotExist) {
+				t.Errorf("Expected error %s for %s, got %v", fs.ErrNotExist.Error(), name, err)
+			}
+		}
+	})
+
+	t.Run("should open not ignored paths", func(t *testing.T) {
+		data, err := fs.ReadFile(filtered, "main.go")
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err.Error())
+		} else if string(data) != "package main" {
+			t.Errorf("Unexpected content: %s", string(data))
+		}
+	})
+
+	t.Run("should not read ignored entries", func(t *testing.T) {
+		entries, err := fs.ReadDir(filtered, "src")
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err.Error())
+		}
+
+		var names []string
+		for _, entry := range entries {
+			names = append(names, entry.Name())
+		}
+
+		expected := []string{"app.go"}
+		if !reflect.DeepEqual(expected, names) {
+			t.Errorf("Expected %v, got %v", expected, names)
+		}
+	})
+
+	t.Run("should read entries in batches", func(t *testing.T) {
+		file, err := filtered.Open(".")
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err.Error())
+		}
+
+		defer file.Close()
+
+		var names []string
+		for {
+			entries, err := file.(fs.ReadDirFile).ReadDir(1)
+			for _, entry := range entries {
+				names = append(names, entry.Name())
+			}
+
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err.Error())
+			}
+			if len(entries) == 0 {
+				t.Fatalf("Expected entries or error")
+			}
+		}
+
+		expected := []string{"assets", "main.go", "src"}
+		if !reflect.DeepEqual(expected, names) {
+			t.Errorf("Expected %v, got %v", expected, names)
+		}
+	})
+
+	t.Run("should not walk ignored paths", func(t *testing.T) {
+		var walked []string
+
+		err := fs.WalkDir(filtered, ".", func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			walked = append(walked, path)
+			return nil
+		})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err.Error())
+		}
+
+		expected := []string{".", "assets", "assets/build", "assets/style.css", "main.go", "src", "src/app.go"}
+		if !reflect.DeepEqual(expected, walked) {
+			t.Errorf("Expected %v, got %v", expected, walked)
+		}
+	})
+}
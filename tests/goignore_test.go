@@ -2,10 +2,11 @@ package tests
 
 import (
 	"errors"
+	"io/fs"
 	"os"
 	"path/filepath"
-	"reflect"
 	"testing"
+	"testing/fstest"
 
 	"github.com/dev-addict/goignore"
 )
@@ -18,54 +19,91 @@ type ContentRulesSet struct {
 var contentRulesSet = ContentRulesSet{
 	"foo\nbar",
 	goignore.Rules{
-		{Raw: "foo"},
-		{Raw: "bar"},
+		{Raw: "foo", Line: 1, Text: "foo"},
+		{Raw: "bar", Line: 2, Text: "bar"},
 	},
 }
 
 var contentRulesSetWithComment = ContentRulesSet{
 	"foo\n# comment\nbar",
 	goignore.Rules{
-		{Raw: "foo"},
-		{Raw: "bar"},
+		{Raw: "foo", Line: 1, Text: "foo"},
+		{Raw: "bar", Line: 3, Text: "bar"},
 	},
 }
 
 var contentRulesSetWithEmptyLine = ContentRulesSet{
 	"foo\n\n# comment\nbar",
 	goignore.Rules{
-		{Raw: "foo"},
-		{Raw: "bar"},
+		{Raw: "foo", Line: 1, Text: "foo"},
+		{Raw: "bar", Line: 4, Text: "bar"},
 	},
 }
 
 var contentRulesSetWithDirectory = ContentRulesSet{
 	"foo\nbar\n\n# comment\nbaz/",
 	goignore.Rules{
-		{Raw: "foo"},
-		{Raw: "bar"},
-		{Raw: "baz/", IsDir: true},
+		{Raw: "foo", Line: 1, Text: "foo"},
+		{Raw: "bar", Line: 2, Text: "bar"},
+		{Raw: "baz/", IsDir: true, Line: 5, Text: "baz/"},
 	},
 }
 
 var contentRulesSetWithNegate = ContentRulesSet{
 	"foo\n!bar\n\n# comment\nbaz/",
 	goignore.Rules{
-		{Raw: "foo"},
-		{Raw: "bar", IsNegate: true},
-		{Raw: "baz/", IsDir: true},
+		{Raw: "foo", Line: 1, Text: "foo"},
+		{Raw: "bar", IsNegate: true, Line: 2, Text: "!bar"},
+		{Raw: "baz/", IsDir: true, Line: 5, Text: "baz/"},
+	},
+}
+
+var contentRulesSetWithDoubleStar = ContentRulesSet{
+	"foo\nfoo/**/bar\nbar",
+	goignore.Rules{
+		{Raw: "foo", Line: 1, Text: "foo"},
+		{Raw: "foo/**/bar", Line: 2, Text: "foo/**/bar"},
+		{Raw: "bar", Line: 3, Text: "bar"},
 	},
 }
 
 var contentRulesSetWithNegateDirectory = ContentRulesSet{
 	"foo\n!bar\n\n# comment\n!baz/",
 	goignore.Rules{
-		{Raw: "foo"},
-		{Raw: "bar", IsNegate: true},
-		{Raw: "baz/", IsNegate: true, IsDir: true},
+		{Raw: "foo", Line: 1, Text: "foo"},
+		{Raw: "bar", IsNegate: true, Line: 2, Text: "!bar"},
+		{Raw: "baz/", IsNegate: true, IsDir: true, Line: 5, Text: "!baz/"},
 	},
 }
 
+func withSource(rules goignore.Rules, source string) goignore.Rules {
+	sourced := make(goignore.Rules, len(rules))
+
+	for i, rule := range rules {
+		rule.Source = source
+		sourced[i] = rule
+	}
+
+	return sourced
+}
+
+// equalRules compares the exported fields of the patterns, parsed patterns also hold their compiled form.
+func equalRules(expected, actual goignore.Rules) bool {
+	if len(expected) != len(actual) {
+		return false
+	}
+
+	for i := range expected {
+		e, a := expected[i], actual[i]
+		if e.Raw != a.Raw || e.IsNegate != a.IsNegate || e.IsDir != a.IsDir || e.IgnoreCase != a.IgnoreCase || e.Source != a.Source ||
+			e.Line != a.Line || e.Text != a.Text || e.Dialect() != a.Dialect() {
+			return false
+		}
+	}
+
+	return true
+}
+
 func TestParse(t *testing.T) {
 	t.Run("should parse valid content", func(t *testing.T) {
 		parsedRules, err := goignore.Parse(contentRulesSet.content)
@@ -73,7 +111,7 @@ func TestParse(t *testing.T) {
 			t.Errorf("Unexpected error: %s", err.Error())
 		}
 
-		if !reflect.DeepEqual(contentRulesSet.rules, *parsedRules) {
+		if !equalRules(contentRulesSet.rules, *parsedRules) {
 			t.Errorf("Content invalidly parsed")
 		}
 	})
@@ -84,7 +122,7 @@ func TestParse(t *testing.T) {
 			t.Errorf("Unexpected error: %s", err.Error())
 		}
 
-		if !reflect.DeepEqual(contentRulesSetWithComment.rules, *parsedRules) {
+		if !equalRules(contentRulesSetWithComment.rules, *parsedRules) {
 			t.Errorf("Content invalidly parsed")
 		}
 	})
@@ -95,7 +133,7 @@ func TestParse(t *testing.T) {
 			t.Errorf("Unexpected error: %s", err.Error())
 		}
 
-		if !reflect.DeepEqual(contentRulesSetWithEmptyLine.rules, *parsedRules) {
+		if !equalRules(contentRulesSetWithEmptyLine.rules, *parsedRules) {
 			t.Errorf("Content invalidly parsed")
 		}
 	})
@@ -106,7 +144,7 @@ func TestParse(t *testing.T) {
 			t.Errorf("Unexpected error: %s", err.Error())
 		}
 
-		if !reflect.DeepEqual(contentRulesSetWithDirectory.rules, *parsedRules) {
+		if !equalRules(contentRulesSetWithDirectory.rules, *parsedRules) {
 			t.Errorf("Content invalidly parsed")
 		}
 	})
@@ -117,7 +155,7 @@ func TestParse(t *testing.T) {
 			t.Errorf("Unexpected error: %s", err.Error())
 		}
 
-		if !reflect.DeepEqual(contentRulesSetWithNegate.rules, *parsedRules) {
+		if !equalRules(contentRulesSetWithNegate.rules, *parsedRules) {
 			t.Errorf("Content invalidly parsed")
 		}
 	})
@@ -128,16 +166,60 @@ func TestParse(t *testing.T) {
 			t.Errorf("Unexpected error: %s", err.Error())
 		}
 
-		if !reflect.DeepEqual(contentRulesSetWithNegateDirectory.rules, *parsedRules) {
+		if !equalRules(contentRulesSetWithNegateDirectory.rules, *parsedRules) {
+			t.Errorf("Content invalidly parsed")
+		}
+	})
+
+	t.Run("should parse valid content with carriage returns", func(t *testing.T) {
+		parsedRules, err := goignore.Parse("foo\r\nbar\r\n")
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err.Error())
+		}
+
+		if !equalRules(contentRulesSet.rules, *parsedRules) {
+			t.Errorf("Content invalidly parsed")
+		}
+	})
+
+	t.Run("should parse valid content with double star", func(t *testing.T) {
+		parsedRules, err := goignore.Parse(contentRulesSetWithDoubleStar.content)
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err.Error())
+		}
+
+		if !equalRules(contentRulesSetWithDoubleStar.rules, *parsedRules) {
 			t.Errorf("Content invalidly parsed")
 		}
 	})
 
-	t.Run("should not parse content with double star syntax", func(t *testing.T) {
-		_, err := goignore.Parse("foo\nfoo/**/bar\nbar")
+	t.Run("should parse double star within a segment like a star", func(t *testing.T) {
+		rules, err := goignore.Parse("foo/bar**\n**.orig")
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err.Error())
+		}
+
+		for path, expected := range map[string]bool{
+			"foo/barbaz":   true,
+			"foo/bar/baz":  true,
+			"foo/baz/bar":  false,
+			"a.orig":       true,
+			"sub/b.orig":   true,
+			"sub/b.orig.c": false,
+		} {
+			if matched, err := rules.Match(path); err != nil {
+				t.Errorf("Unexpected error: %s", err.Error())
+			} else if matched != expected {
+				t.Errorf("Match(%q) should be %t", path, expected)
+			}
+		}
+	})
+
+	t.Run("should not parse double star with a dialect without double star", func(t *testing.T) {
+		_, err := goignore.ParseWithDialect("foo\nfoo/bar**\nbar", goignore.DialectHelm)
 		if err == nil {
 			t.Errorf("Expected error, got nil")
-		} else if !errors.Is(goignore.ErrDoubleStarSyntax, err) {
+		} else if !errors.Is(err, goignore.ErrDoubleStarSyntax) {
 			t.Errorf("Expected error %s, got %s", goignore.ErrDoubleStarSyntax.Error(), err.Error())
 		}
 	})
@@ -146,12 +228,168 @@ func TestParse(t *testing.T) {
 		_, err := goignore.Parse("# comment\nfoo\nbar\n[123")
 		if err == nil {
 			t.Errorf("Expected error, got nil")
-		} else if !errors.Is(filepath.ErrBadPattern, err) {
+		} else if !errors.Is(err, filepath.ErrBadPattern) {
 			t.Errorf("Expected error %s, got %s", filepath.ErrBadPattern.Error(), err.Error())
 		}
 	})
 }
 
+func TestParseWithOptions(t *testing.T) {
+	t.Run("should return position of invalid rule", func(t *testing.T) {
+		_, err := goignore.ParseWithOptions("foo\n!bar/[a-\nbaz/qux**", goignore.ParseOptions{})
+
+		var parseErr *goignore.ParseError
+		if !errors.As(err, &parseErr) {
+			t.Fatalf("Expected parse error, got %v", err)
+		}
+
+		if parseErr.Line != 2 || parseErr.Column != 6 || parseErr.Text != "!bar/[a-" {
+			t.Errorf("Unexpected position %d:%d of %q", parseErr.Line, parseErr.Column, parseErr.Text)
+		}
+		if !errors.Is(err, goignore.ErrBadPattern) {
+			t.Errorf("Expected error %s, got %s", goignore.ErrBadPattern.Error(), err.Error())
+		}
+		if err.Error() != "2:6: syntax error in pattern: !bar/[a-" {
+			t.Errorf("Unexpected error message: %s", err.Error())
+		}
+	})
+
+	t.Run("should return all errors", func(t *testing.T) {
+		_, err := goignore.ParseWithOptions("foo\n!bar/[a-\nbaz/qux**", goignore.ParseOptions{AllErrors: true, Dialect: goignore.DialectHelm})
+
+		var parseErrs goignore.ParseErrors
+		if !errors.As(err, &parseErrs) {
+			t.Fatalf("Expected parse errors, got %v", err)
+		}
+
+		if len(parseErrs) != 2 {
+			t.Fatalf("Expected 2 errors, got %d", len(parseErrs))
+		}
+		if parseErrs[1].Line != 3 || parseErrs[1].Column != 8 {
+			t.Errorf("Unexpected position %d:%d", parseErrs[1].Line, parseErrs[1].Column)
+		}
+		if !errors.Is(err, goignore.ErrBadPattern) {
+			t.Errorf("Expected error %s, got %s", goignore.ErrBadPattern.Error(), err.Error())
+		}
+		if !errors.Is(err, goignore.ErrDoubleStarSyntax) {
+			t.Errorf("Expected error %s, got %s", goignore.ErrDoubleStarSyntax.Error(), err.Error())
+		}
+	})
+
+	t.Run("should parse valid content with all errors", func(t *testing.T) {
+		parsedRules, err := goignore.ParseWithOptions(contentRulesSetWithNegate.content, goignore.ParseOptions{AllErrors: true})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err.Error())
+		}
+
+		if !equalRules(contentRulesSetWithNegate.rules, *parsedRules) {
+			t.Errorf("Content invalidly parsed")
+		}
+	})
+}
+
+func TestParseWithOptionsLenient(t *testing.T) {
+	t.Run("should skip invalid lines", func(t *testing.T) {
+		var warnings goignore.ParseErrors
+
+		parsedRules, err := goignore.ParseWithOptions("foo\n[123\nbar/[a-\nbar", goignore.ParseOptions{Lenient: true, Warnings: &warnings})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err.Error())
+		}
+
+		if len(warnings) != 2 {
+			t.Errorf("Expected 2 warnings, got %d", len(warnings))
+		} else if warnings[0].Line != 2 || warnings[1].Line != 3 {
+			t.Errorf("Unexpected lines %d and %d", warnings[0].Line, warnings[1].Line)
+		}
+
+		expected := goignore.Rules{
+			{Raw: "foo", Line: 1, Text: "foo"},
+			{Raw: "bar", Line: 4, Text: "bar"},
+		}
+		if parsedRules == nil || !equalRules(expected, *parsedRules) {
+			t.Errorf("Content invalidly parsed")
+		}
+	})
+
+	t.Run("should skip invalid lines without warnings", func(t *testing.T) {
+		parsedRules, err := goignore.ParseWithOptions("foo\n[123\nbar", goignore.ParseOptions{Lenient: true})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err.Error())
+		}
+
+		if parsedRules == nil || len(*parsedRules) != 2 {
+			t.Errorf("Content invalidly parsed")
+		}
+	})
+
+	t.Run("should not return warnings without invalid lines", func(t *testing.T) {
+		var warnings goignore.ParseErrors
+
+		parsedRules, err := goignore.ParseWithOptions(contentRulesSet.content, goignore.ParseOptions{Lenient: true, Warnings: &warnings})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err.Error())
+		}
+
+		if len(warnings) != 0 {
+			t.Errorf("Expected no warnings, got %v", warnings)
+		}
+
+		if !equalRules(contentRulesSet.rules, *parsedRules) {
+			t.Errorf("Content invalidly parsed")
+		}
+	})
+}
+
+func TestParseFileWithOptions(t *testing.T) {
+	t.Run("should return position of invalid rule", func(t *testing.T) {
+		file, err := os.Open("./testdata/.contentignore-invalid-rule")
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err.Error())
+		}
+
+		defer file.Close()
+
+		_, err = goignore.ParseFileWithOptions(file, goignore.ParseOptions{AllErrors: true})
+		if err == nil {
+			t.Fatalf("Expected error, got nil")
+		}
+
+		expected := "./testdata/.contentignore-invalid-rule:4:1: syntax error in pattern: [123"
+		if err.Error() != expected {
+			t.Errorf("Expected error %s, got %s", expected, err.Error())
+		}
+	})
+
+	t.Run("should skip invalid lines", func(t *testing.T) {
+		file, err := os.Open("./testdata/.contentignore-invalid-rule")
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err.Error())
+		}
+
+		defer file.Close()
+
+		var warnings goignore.ParseErrors
+
+		parsedRules, err := goignore.ParseFileWithOptions(file, goignore.ParseOptions{Lenient: true, Warnings: &warnings})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err.Error())
+		}
+
+		if !errors.Is(warnings, goignore.ErrBadPattern) {
+			t.Errorf("Expected warning %s, got %v", goignore.ErrBadPattern.Error(), warnings)
+		}
+
+		expected := goignore.Rules{
+			{Raw: "foo", Source: "./testdata/.contentignore-invalid-rule", Line: 2, Text: "foo"},
+			{Raw: "bar", Source: "./testdata/.contentignore-invalid-rule", Line: 3, Text: "bar"},
+		}
+		if parsedRules == nil || !equalRules(expected, *parsedRules) {
+			t.Errorf("Content invalidly parsed")
+		}
+	})
+}
+
 func TestParseFile(t *testing.T) {
 	t.Run("should parse valid content", func(t *testing.T) {
 		file, err := os.Open("./testdata/.contentignore")
@@ -166,7 +404,7 @@ func TestParseFile(t *testing.T) {
 			t.Errorf("Unexpected error: %s", err.Error())
 		}
 
-		if !reflect.DeepEqual(contentRulesSet.rules, *parsedRules) {
+		if !equalRules(withSource(contentRulesSet.rules, "./testdata/.contentignore"), *parsedRules) {
 			t.Errorf("Content invalidly parsed")
 		}
 	})
@@ -184,7 +422,7 @@ func TestParseFile(t *testing.T) {
 			t.Errorf("Unexpected error: %s", err.Error())
 		}
 
-		if !reflect.DeepEqual(contentRulesSetWithComment.rules, *parsedRules) {
+		if !equalRules(withSource(contentRulesSetWithComment.rules, "./testdata/.contentignore-comment"), *parsedRules) {
 			t.Errorf("Content invalidly parsed")
 		}
 	})
@@ -202,7 +440,7 @@ func TestParseFile(t *testing.T) {
 			t.Errorf("Unexpected error: %s", err.Error())
 		}
 
-		if !reflect.DeepEqual(contentRulesSetWithEmptyLine.rules, *parsedRules) {
+		if !equalRules(withSource(contentRulesSetWithEmptyLine.rules, "./testdata/.contentignore-empty-line"), *parsedRules) {
 			t.Errorf("Content invalidly parsed")
 		}
 	})
@@ -220,7 +458,7 @@ func TestParseFile(t *testing.T) {
 			t.Errorf("Unexpected error: %s", err.Error())
 		}
 
-		if !reflect.DeepEqual(contentRulesSetWithDirectory.rules, *parsedRules) {
+		if !equalRules(withSource(contentRulesSetWithDirectory.rules, "./testdata/.contentignore-directory"), *parsedRules) {
 			t.Errorf("Content invalidly parsed")
 		}
 	})
@@ -238,7 +476,7 @@ func TestParseFile(t *testing.T) {
 			t.Errorf("Unexpected error: %s", err.Error())
 		}
 
-		if !reflect.DeepEqual(contentRulesSetWithNegate.rules, *parsedRules) {
+		if !equalRules(withSource(contentRulesSetWithNegate.rules, "./testdata/.contentignore-negate"), *parsedRules) {
 			t.Errorf("Content invalidly parsed")
 		}
 	})
@@ -256,12 +494,12 @@ func TestParseFile(t *testing.T) {
 			t.Errorf("Unexpected error: %s", err.Error())
 		}
 
-		if !reflect.DeepEqual(contentRulesSetWithNegateDirectory.rules, *parsedRules) {
+		if !equalRules(withSource(contentRulesSetWithNegateDirectory.rules, "./testdata/.contentignore-negate-directory"), *parsedRules) {
 			t.Errorf("Content invalidly parsed")
 		}
 	})
 
-	t.Run("should not parse content with double star syntax", func(t *testing.T) {
+	t.Run("should parse valid content with double star", func(t *testing.T) {
 		file, err := os.Open("./testdata/.contentignore-double-star")
 		if err != nil {
 			t.Errorf("Unexpected error: %s", err.Error())
@@ -269,10 +507,46 @@ func TestParseFile(t *testing.T) {
 
 		defer file.Close()
 
-		_, err = goignore.ParseFile(file)
+		parsedRules, err := goignore.ParseFile(file)
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err.Error())
+		}
+
+		if !equalRules(withSource(contentRulesSetWithDoubleStar.rules, "./testdata/.contentignore-double-star"), *parsedRules) {
+			t.Errorf("Content invalidly parsed")
+		}
+	})
+
+	t.Run("should parse content with double star within a segment", func(t *testing.T) {
+		file, err := os.Open("./testdata/.contentignore-invalid-double-star")
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err.Error())
+		}
+
+		defer file.Close()
+
+		rules, err := goignore.ParseFile(file)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err.Error())
+		}
+
+		if matched, err := rules.Match("foo/barbaz"); err != nil || !matched {
+			t.Errorf("Should match foo/barbaz")
+		}
+	})
+
+	t.Run("should not parse content with double star with a dialect without double star", func(t *testing.T) {
+		file, err := os.Open("./testdata/.contentignore-invalid-double-star")
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err.Error())
+		}
+
+		defer file.Close()
+
+		_, err = goignore.ParseFileWithOptions(file, goignore.ParseOptions{Dialect: goignore.DialectHelm})
 		if err == nil {
 			t.Errorf("Expected error, got nil")
-		} else if !errors.Is(goignore.ErrDoubleStarSyntax, err) {
+		} else if !errors.Is(err, goignore.ErrDoubleStarSyntax) {
 			t.Errorf("Expected error %s, got %s", goignore.ErrDoubleStarSyntax.Error(), err.Error())
 		}
 	})
@@ -288,7 +562,7 @@ func TestParseFile(t *testing.T) {
 		_, err = goignore.ParseFile(file)
 		if err == nil {
 			t.Errorf("Expected error, got nil")
-		} else if !errors.Is(filepath.ErrBadPattern, err) {
+		} else if !errors.Is(err, filepath.ErrBadPattern) {
 			t.Errorf("Expected error %s, got %s", filepath.ErrBadPattern.Error(), err.Error())
 		}
 	})
@@ -301,8 +575,43 @@ func TestParseFileFromPath(t *testing.T) {
 			t.Errorf("Unexpected error: %s", err.Error())
 		}
 
-		if !reflect.DeepEqual(contentRulesSet.rules, *parsedRules) {
+		if !equalRules(withSource(contentRulesSet.rules, "./testdata/.contentignore"), *parsedRules) {
+			t.Errorf("Content invalidly parsed")
+		}
+	})
+}
+
+func TestParseFS(t *testing.T) {
+	t.Run("should parse valid content", func(t *testing.T) {
+		parsedRules, err := goignore.ParseFS(os.DirFS("./testdata"), ".contentignore")
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err.Error())
+		}
+
+		if !equalRules(withSource(contentRulesSet.rules, ".contentignore"), *parsedRules) {
+			t.Errorf("Content invalidly parsed")
+		}
+	})
+
+	t.Run("should parse valid content with negate directory", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"foo/.gitignore": {Data: []byte(contentRulesSetWithNegateDirectory.content)},
+		}
+
+		parsedRules, err := goignore.ParseFS(fsys, "foo/.gitignore")
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err.Error())
+		}
+
+		if !equalRules(withSource(contentRulesSetWithNegateDirectory.rules, "foo/.gitignore"), *parsedRules) {
 			t.Errorf("Content invalidly parsed")
 		}
 	})
+
+	t.Run("should not parse missing file", func(t *testing.T) {
+		_, err := goignore.ParseFS(fstest.MapFS{}, ".gitignore")
+		if !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("Expected error %s, got %v", fs.ErrNotExist.Error(), err)
+		}
+	})
 }
@@ -0,0 +1,101 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dev-addict/goignore"
+)
+
+func TestDialectHg(t *testing.T) {
+	t.Run("should match like mercurial", func(t *testing.T) {
+		cases := []struct {
+			content string
+			ignored []string
+			kept    []string
+		}{
+			{`\.orig$`, []string{"foo.orig", "foo/bar.orig"}, []string{"foo.orig.txt"}},
+			{`^build/`, []string{"build/foo", "build/foo/bar"}, []string{"foo/build/bar", "build"}},
+			{`tmp`, []string{"tmp", "foo/tmpfile", "tmp/foo"}, []string{"foo/bar"}},
+			{"syntax: glob\n*.pyc", []string{"foo.pyc", "foo/bar.pyc"}, []string{"foo.py"}},
+			{"syntax: glob\nbuild", []string{"build", "foo/build", "build/foo"}, []string{"rebuild", "builder"}},
+			{"syntax: glob\nfoo/**/bar", []string{"foo/bar", "foo/a/b/bar", "x/foo/a/bar"}, []string{"foo/barx"}},
+			{"syntax: glob\n*.{c,h}", []string{"foo.c", "foo.h"}, []string{"foo.o"}},
+			{"syntax: glob\nfoo[!0-9]", []string{"fooa"}, []string{"foo1"}},
+			{"syntax: rootglob\nbuild", []string{"build", "build/foo"}, []string{"foo/build"}},
+			{"syntax: glob\n*.o\nsyntax: regexp\n^out$", []string{"foo.o", "out"}, []string{"foo/out"}},
+			{"syntax: glob\nre:^dist/\n*.log", []string{"dist/foo", "foo.log"}, []string{"foo/dist/bar"}},
+			{"glob:*.bak\nrootglob:bin", []string{"foo/a.bak", "bin/foo"}, []string{"foo/bin"}},
+			{"syntax: glob\n*.txt # text files\n\\#*", []string{"a.txt", "#foo"}, []string{"a.md"}},
+			{"syntax: glob\né.txt\nrootglob:ü/*.{c,ö}", []string{"é.txt", "a/é.txt", "ü/a.ö"}, []string{"e.txt", "a/ü/a.c"}},
+		}
+
+		for _, c := range cases {
+			rules, err := goignore.ParseWithDialect(c.content, goignore.DialectHg)
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err.Error())
+				continue
+			}
+
+			for _, path := range c.ignored {
+				matched, err := rules.Match(path)
+				if err != nil {
+					t.Errorf("Unexpected error: %s", err.Error())
+				} else if !matched {
+					t.Errorf("%q should match %s", c.content, path)
+				}
+			}
+
+			for _, path := range c.kept {
+				matched, err := rules.Match(path)
+				if err != nil {
+					t.Errorf("Unexpected error: %s", err.Error())
+				} else if matched {
+					t.Errorf("%q should not match %s", c.content, path)
+				}
+			}
+		}
+	})
+
+	t.Run("should keep syntax per file", func(t *testing.T) {
+		if _, err := goignore.ParseWithDialect("syntax: glob", goignore.DialectHg); err != nil {
+			t.Fatalf("Unexpected error: %s", err.Error())
+		}
+
+		// The syntax of the previous file does not leak, so "*.o" is parsed as an invalid regexp.
+		_, err := goignore.ParseWithDialect("*.o", goignore.DialectHg)
+		if !errors.Is(err, goignore.ErrBadPattern) {
+			t.Errorf("Expected error %s, got %v", goignore.ErrBadPattern.Error(), err)
+		}
+	})
+
+	t.Run("should not parse invalid lines", func(t *testing.T) {
+		cases := map[string]error{
+			"foo\n  (bar":       goignore.ErrBadPattern,
+			"syntax: foo":       goignore.ErrUnsupportedSyntax,
+			"include:other":     goignore.ErrUnsupportedSyntax,
+			"syntax: glob\n{a,": goignore.ErrBadPattern,
+		}
+
+		for content, expected := range cases {
+			_, err := goignore.ParseWithDialect(content, goignore.DialectHg)
+
+			var parseErr *goignore.ParseError
+			if !errors.As(err, &parseErr) {
+				t.Errorf("Expected parse error for %q, got %v", content, err)
+				continue
+			}
+
+			if !errors.Is(err, expected) {
+				t.Errorf("Expected error %s, got %s", expected.Error(), err.Error())
+			}
+		}
+
+		_, err := goignore.ParseWithDialect("foo\n  (bar", goignore.DialectHg)
+
+		var parseErr *goignore.ParseError
+		if errors.As(err, &parseErr) && (parseErr.Line != 2 || parseErr.Column != 1) {
+			t.Errorf("Unexpected position %d:%d", parseErr.Line, parseErr.Column)
+		}
+	})
+}
@@ -0,0 +1,120 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/dev-addict/goignore"
+)
+
+func TestIgnoreCase(t *testing.T) {
+	t.Run("should match ignoring case", func(t *testing.T) {
+		cases := []struct {
+			content string
+			dialect goignore.Dialect
+			ignored []string
+			kept    []string
+		}{
+			{"readme.md", goignore.DialectGit, []string{"README.md", "docs/ReadMe.MD"}, []string{"README.txt"}},
+			{"*.PYC", goignore.DialectGit, []string{"foo.pyc", "foo.Pyc", "foo/bar.PYC"}, []string{"foo.py"}},
+			{"tmp*", goignore.DialectGit, []string{"TMP", "Temp/TmpFile"}, []string{"atmp"}},
+			{"/Build/Out/", goignore.DialectGit, []string{"build/out/", "BUILD/OUT/x"}, []string{"src/build/out/"}},
+			{"[a-c]x", goignore.DialectGit, []string{"AX", "bx", "Cx"}, []string{"dx", "DX"}},
+			{"[^a-c]x", goignore.DialectGit, []string{"dx", "DX"}, []string{"AX", "bx"}},
+			{"[!a]*", goignore.DialectGit, []string{"b", "B", "!x"}, []string{"a", "A"}},
+			{"f?o\\*", goignore.DialectGit, []string{"FOO*", "fxo*"}, []string{"foo"}},
+			{"straße", goignore.DialectGit, []string{"STRAẞE", "Straße"}, []string{"strasse"}},
+			{"*k", goignore.DialectGit, []string{"K", "fooK"}, []string{"foo"}},
+			{"ÉTÉ*", goignore.DialectGit, []string{"été.txt"}, []string{"ete.txt"}},
+			{"*.log\n!Important.log", goignore.DialectGit, []string{"debug.LOG"}, []string{"IMPORTANT.LOG"}},
+			{"Vendor\n!vendor/Keep.go", goignore.DialectDocker, []string{"VENDOR/a.go"}, []string{"vendor/keep.GO"}},
+			{"syntax: glob\n*.ORIG\nre:^Dist/", goignore.DialectHg, []string{"foo.orig", "dist/foo"}, []string{"foo/dist/x"}},
+		}
+
+		for _, c := range cases {
+			parsedRules, err := goignore.ParseWithOptions(c.content, goignore.ParseOptions{Dialect: c.dialect, IgnoreCase: true})
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err.Error())
+				continue
+			}
+
+			index := parsedRules.Index()
+
+			for _, path := range c.ignored {
+				matched, err := parsedRules.Match(path)
+				if err != nil {
+					t.Errorf("Unexpected error: %s", err.Error())
+				} else if !matched {
+					t.Errorf("%q should match %s", c.content, path)
+				}
+
+				if matched, err := index.Match(path); err != nil || !matched {
+					t.Errorf("%q index should match %s", c.content, path)
+				}
+			}
+
+			for _, path := range c.kept {
+				matched, err := parsedRules.Match(path)
+				if err != nil {
+					t.Errorf("Unexpected error: %s", err.Error())
+				} else if matched {
+					t.Errorf("%q should not match %s", c.content, path)
+				}
+
+				if matched, err := index.Match(path); err != nil || matched {
+					t.Errorf("%q index should not match %s", c.content, path)
+				}
+			}
+		}
+	})
+
+	t.Run("should match case by default", func(t *testing.T) {
+		parsedRules, err := goignore.Parse("*.PYC")
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err.Error())
+		}
+
+		matched, err := parsedRules.Match("foo.pyc")
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err.Error())
+		} else if matched {
+			t.Errorf("Should not match foo.pyc")
+		}
+	})
+
+	t.Run("should match hand-written pattern ignoring case", func(t *testing.T) {
+		pattern := goignore.Pattern{Raw: "[A-Z]oo", IgnoreCase: true}
+
+		matched, err := pattern.Match("foo")
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err.Error())
+		} else if !matched {
+			t.Errorf("Should match foo")
+		}
+	})
+
+	t.Run("should keep ignoring case for parsed lines", func(t *testing.T) {
+		parsedRules, err := goignore.ParseWithOptions("foo", goignore.ParseOptions{IgnoreCase: true})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err.Error())
+		}
+
+		if err := parsedRules.ParseLine("BAR"); err != nil {
+			t.Fatalf("Unexpected error: %s", err.Error())
+		}
+
+		matched, err := parsedRules.Match("bar")
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err.Error())
+		} else if !matched {
+			t.Errorf("Should match bar")
+		}
+	})
+
+	t.Run("should not match malformed pattern", func(t *testing.T) {
+		pattern := goignore.Pattern{Raw: "[a-", IgnoreCase: true}
+
+		if _, err := pattern.Match("foo"); err == nil {
+			t.Errorf("Expected error")
+		}
+	})
+}
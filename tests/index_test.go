@@ -0,0 +1,101 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/dev-addict/goignore"
+)
+
+func TestIndex(t *testing.T) {
+	paths := []string{
+		"node_modules", "node_modules/foo/index.js", "src/node_modules", "foo.pyc", "src/foo.pyc", "foo.py",
+		"build", "build/out.o", "src/build", "src/build/out.o", "docs/api/index.md", "docs/index.md",
+		"archive.tar.gz", "archive.gz", "vendor/keep.go", "vendor/drop.go", "tmpfile", "src/tmp/x", ".env",
+		"a/b/c/d.txt", "logs/important.log", "logs/debug.log", "keep.pyc", "",
+	}
+
+	t.Run("should match like the rules", func(t *testing.T) {
+		contents := []string{
+			"node_modules\n*.pyc\n!keep.pyc\n/build/\ndocs/**/*.md\n!docs/index.md",
+			"*.tar.gz\ntmp*\nsrc/tmp/\n*\n!*/\n!*.go\nvendor/drop.go",
+			"logs/\n!logs/important.log\n.env\na/**/d.txt\n**/build",
+			"/a/b\n/a/b/c\n!/a/b/c/d.txt\n[a-c]/b/\n\\#foo",
+			"ke?p.pyc\narch*.gz\n!archive.g[z]\nnode_*/\nde*.log",
+		}
+
+		for _, content := range contents {
+			parsedRules, err := goignore.Parse(content)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err.Error())
+			}
+
+			index := parsedRules.Index()
+
+			for _, path := range paths {
+				for _, isDir := range []bool{false, true} {
+					expected, err := parsedRules.MatchPath(path, isDir)
+					if err != nil {
+						t.Fatalf("Unexpected error: %s", err.Error())
+					}
+
+					matched, err := index.MatchPath(path, isDir)
+					if err != nil {
+						t.Errorf("Unexpected error: %s", err.Error())
+					} else if matched != expected {
+						t.Errorf("%q should match %s (dir %t) like the rules: %t", content, path, isDir, expected)
+					}
+				}
+			}
+		}
+	})
+
+	t.Run("should match with dialect", func(t *testing.T) {
+		parsedRules, err := goignore.ParseWithDialect("vendor\n!vendor/keep.go", goignore.DialectDocker)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err.Error())
+		}
+
+		index := parsedRules.Index()
+
+		matched, err := index.Match("vendor/keep.go")
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err.Error())
+		} else if matched {
+			t.Errorf("Should not match vendor/keep.go")
+		}
+
+		matched, err = index.Match("vendor/drop.go")
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err.Error())
+		} else if !matched {
+			t.Errorf("Should match vendor/drop.go")
+		}
+	})
+
+	t.Run("should not change with the rules", func(t *testing.T) {
+		parsedRules, err := goignore.Parse("foo")
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err.Error())
+		}
+
+		index := parsedRules.Index()
+		if err := parsedRules.ParseLine("bar"); err != nil {
+			t.Fatalf("Unexpected error: %s", err.Error())
+		}
+
+		matched, err := index.Match("bar")
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err.Error())
+		} else if matched {
+			t.Errorf("Should not match bar")
+		}
+	})
+
+	t.Run("should return pattern errors", func(t *testing.T) {
+		index := (&goignore.Rules{{Raw: "[123"}}).Index()
+
+		if _, err := index.Match("foo"); err == nil {
+			t.Errorf("Expected error")
+		}
+	})
+}
@@ -0,0 +1,160 @@
+package tests
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/dev-addict/goignore"
+)
+
+func TestMatcher(t *testing.T) {
+	t.Run("should match like the rules", func(t *testing.T) {
+		parsedRules, err := goignore.Parse("*.log\n!important.log\nbuild/\n/docs/**/*.md")
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err.Error())
+		}
+
+		matcher, err := goignore.Compile(parsedRules)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err.Error())
+		}
+
+		for _, path := range []string{"foo.log", "important.log", "build/", "build/foo", "docs/a/b.md", "src/docs/b.md"} {
+			expected, err := parsedRules.Match(path)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err.Error())
+			}
+
+			matched, err := matcher.Match(path)
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err.Error())
+			} else if matched != expected {
+				t.Errorf("Should match %s like the rules: %t", path, expected)
+			}
+		}
+	})
+
+	t.Run("should not change with the rules", func(t *testing.T) {
+		builder := goignore.Rules{{Raw: "foo"}}
+
+		matcher, err := goignore.Compile(&builder)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err.Error())
+		}
+
+		builder[0].Raw = "bar"
+		if err := builder.ParseLine("baz"); err != nil {
+			t.Fatalf("Unexpected error: %s", err.Error())
+		}
+
+		for path, expected := range map[string]bool{"foo": true, "bar": false, "baz": false} {
+			matched, err := matcher.Match(path)
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err.Error())
+			} else if matched != expected {
+				t.Errorf("Match of %s should be %t", path, expected)
+			}
+		}
+
+		patterns := matcher.Patterns()
+		patterns[0].Raw = "qux"
+		if len(matcher.Patterns()) != 1 || matcher.Patterns()[0].Raw != "foo" {
+			t.Errorf("Patterns should be a copy")
+		}
+	})
+
+	t.Run("should explain with copied pattern", func(t *testing.T) {
+		matcher, err := goignore.Compile(&goignore.Rules{{Raw: "foo"}})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err.Error())
+		}
+
+		explanation, err := matcher.Explain("foo")
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err.Error())
+		}
+		if !explanation.Ignored || explanation.Pattern == nil {
+			t.Fatalf("Should be ignored by a pattern")
+		}
+
+		explanation.Pattern.Raw = "bar"
+		if matched, err := matcher.Match("foo"); err != nil || !matched {
+			t.Errorf("Should still match foo")
+		}
+	})
+
+	t.Run("should not compile invalid pattern", func(t *testing.T) {
+		cases := map[string]error{
+			"[123": goignore.ErrBadPattern,
+			`foo\`: goignore.ErrBadPattern,
+		}
+
+		for raw, expected := range cases {
+			_, err := goignore.Compile(&goignore.Rules{{Raw: "foo"}, {Raw: raw, Line: 2}})
+
+			var parseErr *goignore.ParseError
+			if !errors.As(err, &parseErr) {
+				t.Errorf("Expected parse error for %s, got %v", raw, err)
+				continue
+			}
+
+			if !errors.Is(err, expected) {
+				t.Errorf("Expected error %s, got %s", expected.Error(), err.Error())
+			}
+			if parseErr.Line != 2 || parseErr.Text != raw {
+				t.Errorf("Unexpected line %d: %s", parseErr.Line, parseErr.Text)
+			}
+		}
+	})
+
+	t.Run("should match concurrently", func(t *testing.T) {
+		builder, err := goignore.Parse("*.log\n!important.log\nbuild/\nnode_modules")
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err.Error())
+		}
+
+		matcher, err := goignore.Compile(builder)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err.Error())
+		}
+
+		var wg sync.WaitGroup
+
+		// The builder keeps changing while the Matcher is shared, which the race detector would report.
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for i := 0; i < 100; i++ {
+				if err := builder.ParseLine(fmt.Sprintf("file%d", i)); err != nil {
+					t.Errorf("Unexpected error: %s", err.Error())
+				}
+				(*builder)[0].Raw = fmt.Sprintf("*.log%d", i)
+			}
+		}()
+
+		for g := 0; g < 8; g++ {
+			wg.Add(1)
+			go func(g int) {
+				defer wg.Done()
+
+				for i := 0; i < 200; i++ {
+					path := fmt.Sprintf("src/%d/debug.log", g*i)
+					if matched, err := matcher.Match(path); err != nil || !matched {
+						t.Errorf("Should match %s", path)
+					}
+					if matched, err := matcher.MatchPath("src/important.log", false); err != nil || matched {
+						t.Errorf("Should not match src/important.log")
+					}
+					if _, err := matcher.Explain("node_modules/foo"); err != nil {
+						t.Errorf("Unexpected error: %s", err.Error())
+					}
+				}
+			}(g)
+		}
+
+		wg.Wait()
+	})
+}
@@ -0,0 +1,321 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"testing/fstest"
+
+	"github.com/dev-addict/goignore"
+)
+
+// collectParallel returns an fs.WalkDirFunc which records the visited paths relative to root.
+func collectParallel(root string, walked *[]string) fs.WalkDirFunc {
+	var mu sync.Mutex
+
+	return func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		*walked = append(*walked, filepath.ToSlash(rel))
+		return nil
+	}
+}
+
+func createParallelTree(t *testing.T) string {
+	t.Helper()
+
+	var files []string
+	for i := 0; i < 5; i++ {
+		for j := 0; j < 5; j++ {
+			files = append(files,
+				fmt.Sprintf("src/pkg%d/sub%d/main.go", i, j),
+				fmt.Sprintf("src/pkg%d/sub%d/debug.log", i, j),
+				fmt.Sprintf("src/pkg%d/sub%d/gen/out.go", i, j),
+			)
+		}
+	}
+	files = append(files, "build/out", "important.log", "src/important.log")
+
+	root := createTree(t, files...)
+
+	ignoreFiles := map[string]string{
+		".gitignore":               "*.log\n/build/\n",
+		"src/.gitignore":           "!important.log\n",
+		"src/pkg1/.gitignore":      "gen/\n",
+		"src/pkg3/.gitignore":      "sub2/\n",
+		"src/pkg3/sub4/.gitignore": "!debug.log\n",
+	}
+
+	for name, content := range ignoreFiles {
+		if err := os.WriteFile(filepath.Join(root, filepath.FromSlash(name)), []byte(content), 0o644); err != nil {
+			t.Fatalf("Unexpected error: %s", err.Error())
+		}
+	}
+
+	return root
+}
+
+func TestWalkParallel(t *testing.T) {
+	t.Run("should walk like Walk", func(t *testing.T) {
+		root := createParallelTree(t)
+
+		rules, err := goignore.Parse("*.log\nbuild/\ngen/")
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err.Error())
+		}
+
+		var expected []string
+		if err := goignore.Walk(root, rules, collectParallel(root, &expected)); err != nil {
+			t.Fatalf("Unexpected error: %s", err.Error())
+		}
+		sort.Strings(expected)
+
+		for _, workers := range []int{0, 1, 8} {
+			var walked []string
+			err := goignore.WalkParallel(context.Background(), root, rules, goignore.WalkOptions{Workers: workers}, collectParallel(root, &walked))
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err.Error())
+			}
+
+			sort.Strings(walked)
+			if !reflect.DeepEqual(expected, walked) {
+				t.Errorf("Expected %v, got %v", expected, walked)
+			}
+		}
+	})
+
+	t.Run("should load ignore files before their children", func(t *testing.T) {
+		root := createParallelTree(t)
+
+		var expected []string
+		if err := goignore.WalkTree(root, goignore.NewTree(".gitignore"), collectParallel(root, &expected)); err != nil {
+			t.Fatalf("Unexpected error: %s", err.Error())
+		}
+		sort.Strings(expected)
+
+		for _, workers := range []int{1, 8} {
+			var walked []string
+			err := goignore.WalkTreeParallel(context.Background(), root, goignore.NewTree(".gitignore"), goignore.WalkOptions{Workers: workers}, collectParallel(root, &walked))
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err.Error())
+			}
+
+			sort.Strings(walked)
+			if !reflect.DeepEqual(expected, walked) {
+				t.Errorf("Expected %v, got %v", expected, walked)
+			}
+		}
+	})
+
+	t.Run("should stop on cancellation", func(t *testing.T) {
+		root := createParallelTree(t)
+		ctx, cancel := context.WithCancel(context.Background())
+
+		var mu sync.Mutex
+		calls := 0
+
+		err := goignore.WalkTreeParallel(ctx, root, goignore.NewTree(".gitignore"), goignore.WalkOptions{Workers: 4}, func(path string, d fs.DirEntry, err error) error {
+			mu.Lock()
+			defer mu.Unlock()
+
+			calls++
+			if calls == 3 {
+				cancel()
+			}
+			return err
+		})
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected error %s, got %v", context.Canceled.Error(), err)
+		}
+	})
+
+	t.Run("should not walk with cancelled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := goignore.WalkParallel(ctx, t.TempDir(), &goignore.Rules{}, goignore.WalkOptions{}, func(path string, d fs.DirEntry, err error) error {
+			t.Errorf("Should not visit %s", path)
+			return nil
+		})
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected error %s, got %v", context.Canceled.Error(), err)
+		}
+	})
+
+	t.Run("should stop with the error of fn", func(t *testing.T) {
+		root := createParallelTree(t)
+		expected := errors.New("stop")
+
+		err := goignore.WalkParallel(context.Background(), root, &goignore.Rules{}, goignore.WalkOptions{Workers: 4}, func(path string, d fs.DirEntry, err error) error {
+			if filepath.Base(path) == "main.go" {
+				return expected
+			}
+			return err
+		})
+		if !errors.Is(err, expected) {
+			t.Errorf("Expected error %s, got %v", expected.Error(), err)
+		}
+	})
+
+	t.Run("should skip directories and stop with SkipAll", func(t *testing.T) {
+		root := createParallelTree(t)
+
+		var walked []string
+		collect := collectParallel(root, &walked)
+
+		err := goignore.WalkParallel(context.Background(), root, &goignore.Rules{}, goignore.WalkOptions{Workers: 4}, func(path string, d fs.DirEntry, err error) error {
+			if d.IsDir() && d.Name() == "src" {
+				return fs.SkipDir
+			}
+			return collect(path, d, err)
+		})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err.Error())
+		}
+
+		sort.Strings(walked)
+		expected := []string{".", ".gitignore", "build", "build/out", "important.log"}
+		if !reflect.DeepEqual(expected, walked) {
+			t.Errorf("Expected %v, got %v", expected, walked)
+		}
+
+		err = goignore.WalkParallel(context.Background(), root, &goignore.Rules{}, goignore.WalkOptions{Workers: 4}, func(path string, d fs.DirEntry, err error) error {
+			return fs.SkipAll
+		})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err.Error())
+		}
+	})
+
+	t.Run("should not walk with invalid rules", func(t *testing.T) {
+		err := goignore.WalkParallel(context.Background(), t.TempDir(), &goignore.Rules{{Raw: "[123"}}, goignore.WalkOptions{}, func(path string, d fs.DirEntry, err error) error {
+			return err
+		})
+		if !errors.Is(err, goignore.ErrBadPattern) {
+			t.Errorf("Expected error %s, got %v", goignore.ErrBadPattern.Error(), err)
+		}
+	})
+}
+
+func TestWalkParallelFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"root/.gitignore":         {Data: []byte("*.log\n")},
+		"root/main.go":            {},
+		"root/debug.log":          {},
+		"root/src/.gitignore":     {Data: []byte("!keep.log\n[123\n/gen/\n")},
+		"root/src/keep.log":       {},
+		"root/src/drop.log":       {},
+		"root/src/gen/out.go":     {},
+		"root/src/pkg/gen/out.go": {},
+		"root/vendor/lib/lib.go":  {},
+		"root/vendor/lib/lib.log": {},
+	}
+
+	collect := func(walked *[]string) fs.WalkDirFunc {
+		var mu sync.Mutex
+
+		return func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			*walked = append(*walked, path)
+			return nil
+		}
+	}
+
+	t.Run("should walk like WalkFS", func(t *testing.T) {
+		rules, err := goignore.Parse("*.log\ngen/")
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err.Error())
+		}
+
+		var expected, walked []string
+		if err := goignore.WalkFS(fsys, "root", rules, collect(&expected)); err != nil {
+			t.Fatalf("Unexpected error: %s", err.Error())
+		}
+
+		if err := goignore.WalkParallelFS(context.Background(), fsys, "root", rules, goignore.WalkOptions{Workers: 3}, collect(&walked)); err != nil {
+			t.Errorf("Unexpected error: %s", err.Error())
+		}
+
+		sort.Strings(expected)
+		sort.Strings(walked)
+		if !reflect.DeepEqual(expected, walked) {
+			t.Errorf("Expected %v, got %v", expected, walked)
+		}
+	})
+
+	t.Run("should match dotfiles at the root", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			".gitignore": {Data: []byte(".env\n")},
+			".env":       {},
+			".git/HEAD":  {},
+			"main.go":    {},
+		}
+
+		rules, err := goignore.Parse(".git/\n.env")
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err.Error())
+		}
+
+		var walked []string
+		if err := goignore.WalkParallelFS(context.Background(), fsys, ".", rules, goignore.WalkOptions{Workers: 3}, collect(&walked)); err != nil {
+			t.Errorf("Unexpected error: %s", err.Error())
+		}
+
+		sort.Strings(walked)
+		expected := []string{".", ".gitignore", "main.go"}
+		if !reflect.DeepEqual(expected, walked) {
+			t.Errorf("Expected %v, got %v", expected, walked)
+		}
+
+		walked = nil
+		if err := goignore.WalkTreeParallelFS(context.Background(), fsys, ".", goignore.NewTree(".gitignore"), goignore.WalkOptions{Workers: 3}, collect(&walked)); err != nil {
+			t.Errorf("Unexpected error: %s", err.Error())
+		}
+
+		sort.Strings(walked)
+		expected = []string{".", ".git", ".git/HEAD", ".gitignore", "main.go"}
+		if !reflect.DeepEqual(expected, walked) {
+			t.Errorf("Expected %v, got %v", expected, walked)
+		}
+	})
+
+	t.Run("should walk like WalkTreeFS", func(t *testing.T) {
+		var expected, walked []string
+		if err := goignore.WalkTreeFS(fsys, "root", goignore.NewTree(".gitignore"), collect(&expected)); err != nil {
+			t.Fatalf("Unexpected error: %s", err.Error())
+		}
+
+		if err := goignore.WalkTreeParallelFS(context.Background(), fsys, "root", goignore.NewTree(".gitignore"), goignore.WalkOptions{Workers: 3}, collect(&walked)); err != nil {
+			t.Errorf("Unexpected error: %s", err.Error())
+		}
+
+		sort.Strings(expected)
+		sort.Strings(walked)
+		if !reflect.DeepEqual(expected, walked) {
+			t.Errorf("Expected %v, got %v", expected, walked)
+		}
+	})
+}
@@ -134,6 +134,78 @@ func TestPattern(t *testing.T) {
 			}
 		})
 
+		t.Run("Should match leading double star", func(t *testing.T) {
+			pattern := goignore.Pattern{
+				Raw: "**/foo",
+			}
+
+			for _, path := range []string{"foo", "bar/foo", "bar/baz/foo"} {
+				matched, err := pattern.Match(path)
+				if err != nil {
+					t.Errorf("Unexpected error: %s", err.Error())
+				} else if !matched {
+					t.Errorf("Should match %s", path)
+				}
+			}
+
+			for _, path := range []string{"bar", "foo/bar", "foobar"} {
+				matched, err := pattern.Match(path)
+				if err != nil {
+					t.Errorf("Unexpected error: %s", err.Error())
+				} else if matched {
+					t.Errorf("Should not match %s", path)
+				}
+			}
+		})
+
+		t.Run("Should match trailing double star", func(t *testing.T) {
+			pattern := goignore.Pattern{
+				Raw: "foo/**",
+			}
+
+			for _, path := range []string{"foo/bar", "foo/bar/baz"} {
+				matched, err := pattern.Match(path)
+				if err != nil {
+					t.Errorf("Unexpected error: %s", err.Error())
+				} else if !matched {
+					t.Errorf("Should match %s", path)
+				}
+			}
+
+			for _, path := range []string{"foo", "bar/foo/baz", "foobar/baz"} {
+				matched, err := pattern.Match(path)
+				if err != nil {
+					t.Errorf("Unexpected error: %s", err.Error())
+				} else if matched {
+					t.Errorf("Should not match %s", path)
+				}
+			}
+		})
+
+		t.Run("Should match middle double star", func(t *testing.T) {
+			pattern := goignore.Pattern{
+				Raw: "foo/**/*.md",
+			}
+
+			for _, path := range []string{"foo/bar.md", "foo/bar/baz.md", "foo/bar/baz/qux.md"} {
+				matched, err := pattern.Match(path)
+				if err != nil {
+					t.Errorf("Unexpected error: %s", err.Error())
+				} else if !matched {
+					t.Errorf("Should match %s", path)
+				}
+			}
+
+			for _, path := range []string{"bar.md", "bar/foo/baz.md", "foo/bar/baz.go"} {
+				matched, err := pattern.Match(path)
+				if err != nil {
+					t.Errorf("Unexpected error: %s", err.Error())
+				} else if matched {
+					t.Errorf("Should not match %s", path)
+				}
+			}
+		})
+
 		t.Run("Should match without negation consideration", func(t *testing.T) {
 			pattern := goignore.Pattern{
 				Raw:      "foo",
@@ -154,5 +226,151 @@ func TestPattern(t *testing.T) {
 				t.Errorf("Should not match")
 			}
 		})
+		t.Run("Should match literal, prefix and suffix segments", func(t *testing.T) {
+			cases := []struct {
+				raw     string
+				matched []string
+				kept    []string
+			}{
+				{`\#foo`, []string{"#foo", "bar/#foo"}, []string{"foo", `\#foo`}},
+				{`foo\*`, []string{"foo*"}, []string{"foobar"}},
+				{"*.go", []string{"main.go", "cmd/main.go", ".go"}, []string{"main.go.txt"}},
+				{`*\?`, []string{"foo?"}, []string{"foo"}},
+				{"temp*", []string{"temp", "temporary", "foo/temp1"}, []string{"atemp"}},
+				{"foo/*.go", []string{"foo/main.go"}, []string{"bar/foo/main.go", "foo/bar/main.go"}},
+				{"foo/ba[rz]", []string{"foo/bar", "foo/baz"}, []string{"foo/bax"}},
+			}
+
+			for _, c := range cases {
+				pattern := goignore.Pattern{Raw: c.raw}
+				rules, err := goignore.Parse(c.raw)
+				if err != nil {
+					t.Fatalf("Unexpected error: %s", err.Error())
+				}
+
+				// Hand-written and parsed patterns must agree.
+				for _, p := range []goignore.Pattern{pattern, (*rules)[0]} {
+					for _, path := range c.matched {
+						if matched, err := p.Match(path); err != nil || !matched {
+							t.Errorf("%q should match %s", c.raw, path)
+						}
+					}
+					for _, path := range c.kept {
+						if matched, err := p.Match(path); err != nil || matched {
+							t.Errorf("%q should not match %s", c.raw, path)
+						}
+					}
+				}
+			}
+		})
+
+		t.Run("Should match changed parsed pattern", func(t *testing.T) {
+			rules, err := goignore.Parse("foo")
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err.Error())
+			}
+
+			pattern := (*rules)[0]
+			pattern.Raw = "bar"
+
+			matched, err := pattern.Match("bar")
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err.Error())
+			} else if !matched {
+				t.Errorf("Should match")
+			}
+		})
+	})
+
+	t.Run("MatchPath", func(t *testing.T) {
+		t.Run("Should match directory pattern only for directories", func(t *testing.T) {
+			pattern := goignore.Pattern{
+				Raw:   "build/",
+				IsDir: true,
+			}
+
+			matched, err := pattern.MatchPath("build", true)
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err.Error())
+			} else if !matched {
+				t.Errorf("Should match build directory")
+			}
+
+			matched, err = pattern.MatchPath("build", false)
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err.Error())
+			} else if matched {
+				t.Errorf("Should not match build file")
+			}
+
+			matched, err = pattern.MatchPath("foo/build", true)
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err.Error())
+			} else if !matched {
+				t.Errorf("Should match foo/build directory")
+			}
+		})
+
+		t.Run("Should match directory pattern without trailing slash", func(t *testing.T) {
+			pattern := goignore.Pattern{
+				Raw:   "build",
+				IsDir: true,
+			}
+
+			matched, err := pattern.MatchPath("build", true)
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err.Error())
+			} else if !matched {
+				t.Errorf("Should match build directory")
+			}
+
+			matched, err = pattern.MatchPath("build", false)
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err.Error())
+			} else if matched {
+				t.Errorf("Should not match build file")
+			}
+		})
+
+		t.Run("Should match file pattern for files and directories", func(t *testing.T) {
+			pattern := goignore.Pattern{
+				Raw: "build",
+			}
+
+			matched, err := pattern.MatchPath("build", true)
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err.Error())
+			} else if !matched {
+				t.Errorf("Should match build directory")
+			}
+
+			matched, err = pattern.MatchPath("build", false)
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err.Error())
+			} else if !matched {
+				t.Errorf("Should match build file")
+			}
+		})
+
+		t.Run("Should match anchored directory pattern", func(t *testing.T) {
+			pattern := goignore.Pattern{
+				Raw:   "/build/",
+				IsDir: true,
+			}
+
+			matched, err := pattern.MatchPath("build", true)
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err.Error())
+			} else if !matched {
+				t.Errorf("Should match build directory")
+			}
+
+			matched, err = pattern.MatchPath("foo/build", true)
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err.Error())
+			} else if matched {
+				t.Errorf("Should not match foo/build directory")
+			}
+		})
 	})
 }
@@ -2,6 +2,7 @@ package tests
 
 import (
 	"errors"
+	"path/filepath"
 	"testing"
 
 	"github.com/dev-addict/goignore"
@@ -39,8 +40,8 @@ func TestRules(t *testing.T) {
 			}
 		})
 
-		t.Run("should not parse double star syntax", func(t *testing.T) {
-			err := rules.ParseLine("foo/**/bar")
+		t.Run("should not parse double star syntax with helm", func(t *testing.T) {
+			err := rules.ParseLineWithDialect("foo/bar**", goignore.DialectHelm)
 			if !errors.Is(err, goignore.ErrDoubleStarSyntax) {
 				t.Errorf("Unexpected error: %s", err.Error())
 			} else if err == nil {
@@ -133,6 +134,154 @@ func TestRules(t *testing.T) {
 			}
 		})
 
+		t.Run("should parse double star pattern", func(t *testing.T) {
+			err := rules.ParseLine("foo/**/bar")
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err.Error())
+			}
+
+			rulesLength++
+
+			if len(rules) != rulesLength {
+				t.Errorf("Rules should have %d item", rulesLength)
+			} else if rules[rulesLength-1].Raw != "foo/**/bar" {
+				t.Errorf("Rule should be foo/**/bar")
+			}
+		})
+
+		t.Run("should parse escaped hash pattern", func(t *testing.T) {
+			err := rules.ParseLine("\\#notes")
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err.Error())
+			}
+
+			rulesLength++
+
+			if len(rules) != rulesLength {
+				t.Errorf("Rules should have %d item", rulesLength)
+			} else if rules[rulesLength-1].Raw != "\\#notes" {
+				t.Errorf("Rule should be \\#notes")
+			}
+		})
+
+		t.Run("should parse escaped exclamation mark pattern", func(t *testing.T) {
+			err := rules.ParseLine("\\!important")
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err.Error())
+			}
+
+			rulesLength++
+
+			if len(rules) != rulesLength {
+				t.Errorf("Rules should have %d item", rulesLength)
+			} else if rules[rulesLength-1].Raw != "\\!important" {
+				t.Errorf("Rule should be \\!important")
+			} else if rules[rulesLength-1].IsNegate {
+				t.Errorf("Rule should not be negate")
+			}
+		})
+
+		t.Run("should trim trailing spaces", func(t *testing.T) {
+			err := rules.ParseLine("foo   ")
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err.Error())
+			}
+
+			rulesLength++
+
+			if len(rules) != rulesLength {
+				t.Errorf("Rules should have %d item", rulesLength)
+			} else if rules[rulesLength-1].Raw != "foo" {
+				t.Errorf("Rule should be foo")
+			}
+		})
+
+		t.Run("should keep trailing tabs", func(t *testing.T) {
+			err := rules.ParseLine("foo \t ")
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err.Error())
+			}
+
+			rulesLength++
+
+			if len(rules) != rulesLength {
+				t.Errorf("Rules should have %d item", rulesLength)
+			} else if rules[rulesLength-1].Raw != "foo \t" {
+				t.Errorf("Rule should be \"foo \\t\"")
+			}
+		})
+
+		t.Run("should keep escaped trailing space", func(t *testing.T) {
+			err := rules.ParseLine("foo\\  ")
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err.Error())
+			}
+
+			rulesLength++
+
+			if len(rules) != rulesLength {
+				t.Errorf("Rules should have %d item", rulesLength)
+			} else if rules[rulesLength-1].Raw != "foo\\ " {
+				t.Errorf("Rule should be foo\\ ")
+			}
+		})
+
+		t.Run("should not keep trailing space after escaped backslash", func(t *testing.T) {
+			err := rules.ParseLine("foo\\\\ ")
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err.Error())
+			}
+
+			rulesLength++
+
+			if len(rules) != rulesLength {
+				t.Errorf("Rules should have %d item", rulesLength)
+			} else if rules[rulesLength-1].Raw != "foo\\\\" {
+				t.Errorf("Rule should be foo\\\\")
+			}
+		})
+
+		t.Run("should keep original line text", func(t *testing.T) {
+			err := rules.ParseLine("!foo/  ")
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err.Error())
+			}
+
+			rulesLength++
+
+			if len(rules) != rulesLength {
+				t.Errorf("Rules should have %d item", rulesLength)
+			} else if rules[rulesLength-1].Text != "!foo/  " {
+				t.Errorf("Rule text should be \"!foo/  \"")
+			}
+		})
+
+		t.Run("should keep leading spaces", func(t *testing.T) {
+			err := rules.ParseLine("  foo")
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err.Error())
+			}
+
+			rulesLength++
+
+			if len(rules) != rulesLength {
+				t.Errorf("Rules should have %d item", rulesLength)
+			} else if rules[rulesLength-1].Raw != "  foo" {
+				t.Errorf("Rule should be \"  foo\"")
+			}
+		})
+
+		t.Run("should not parse whitespace line", func(t *testing.T) {
+			err := rules.ParseLine("    ")
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err.Error())
+			}
+
+			if len(rules) != rulesLength {
+				t.Errorf("Rules should have %d item", rulesLength)
+			}
+		})
+
 		t.Run("should parse pattern with \"/\" prefix", func(t *testing.T) {
 			err := rules.ParseLine("/foo")
 			if err != nil {
@@ -243,8 +392,76 @@ func TestRules(t *testing.T) {
 			if err != nil {
 				t.Errorf("Unexpected error: %s", err.Error())
 			}
+			if !matched {
+				t.Errorf("Should match bar/foo/bar")
+			}
+		})
+
+		t.Run("should match paths inside ignored directories", func(t *testing.T) {
+			rules = goignore.Rules{
+				{Raw: "vendor/", IsDir: true},
+			}
+
+			matched, err := rules.Match("vendor/a/b/c.go")
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err.Error())
+			}
+			if !matched {
+				t.Errorf("Should match vendor/a/b/c.go")
+			}
+
+			matched, err = rules.Match("foo/vendor/c.go")
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err.Error())
+			}
+			if !matched {
+				t.Errorf("Should match foo/vendor/c.go")
+			}
+
+			matched, err = rules.Match("vendors/c.go")
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err.Error())
+			}
+			if matched {
+				t.Errorf("Should not match vendors/c.go")
+			}
+		})
+
+		t.Run("should not re-include paths inside ignored directories", func(t *testing.T) {
+			rules = goignore.Rules{
+				{Raw: "vendor/", IsDir: true},
+				{Raw: "vendor/a/keep.go", IsNegate: true},
+			}
+
+			matched, err := rules.Match("vendor/a/keep.go")
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err.Error())
+			}
+			if !matched {
+				t.Errorf("Should match vendor/a/keep.go")
+			}
+
+			rules = goignore.Rules{
+				{Raw: "/vendor/*"},
+				{Raw: "/vendor/a/", IsNegate: true, IsDir: true},
+				{Raw: "/vendor/a/*"},
+				{Raw: "/vendor/a/keep.go", IsNegate: true},
+			}
+
+			matched, err = rules.Match("vendor/a/keep.go")
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err.Error())
+			}
 			if matched {
-				t.Errorf("Should not match bar/foo/bar")
+				t.Errorf("Should not match vendor/a/keep.go")
+			}
+
+			matched, err = rules.Match("vendor/b/c.go")
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err.Error())
+			}
+			if !matched {
+				t.Errorf("Should match vendor/b/c.go")
 			}
 		})
 
@@ -262,6 +479,42 @@ func TestRules(t *testing.T) {
 			}
 		})
 
+		t.Run("should match last matching pattern", func(t *testing.T) {
+			rules = goignore.Rules{
+				{Raw: "*.log"},
+				{Raw: "important.log", IsNegate: true},
+			}
+
+			matched, err := rules.Match("important.log")
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err.Error())
+			}
+			if matched {
+				t.Errorf("Should not match important.log")
+			}
+
+			matched, err = rules.Match("debug.log")
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err.Error())
+			}
+			if !matched {
+				t.Errorf("Should match debug.log")
+			}
+
+			rules = goignore.Rules{
+				{Raw: "important.log", IsNegate: true},
+				{Raw: "*.log"},
+			}
+
+			matched, err = rules.Match("important.log")
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err.Error())
+			}
+			if !matched {
+				t.Errorf("Should match important.log")
+			}
+		})
+
 		t.Run("should match negate pattern with \"/\" prefix", func(t *testing.T) {
 			rules = goignore.Rules{
 				{Raw: "/foo", IsNegate: true},
@@ -320,4 +573,181 @@ func TestRules(t *testing.T) {
 			}
 		})
 	})
+
+	t.Run("MatchPath", func(t *testing.T) {
+		t.Run("should match escaped patterns literally", func(t *testing.T) {
+			rules = goignore.Rules{}
+
+			for _, line := range []string{"\\#notes", "\\!important", "foo\\ "} {
+				if err := rules.ParseLine(line); err != nil {
+					t.Errorf("Unexpected error: %s", err.Error())
+				}
+			}
+
+			for _, path := range []string{"#notes", "!important", "foo "} {
+				matched, err := rules.MatchPath(path, false)
+				if err != nil {
+					t.Errorf("Unexpected error: %s", err.Error())
+				}
+				if !matched {
+					t.Errorf("Should match %q", path)
+				}
+			}
+
+			for _, path := range []string{"notes", "important", "foo"} {
+				matched, err := rules.MatchPath(path, false)
+				if err != nil {
+					t.Errorf("Unexpected error: %s", err.Error())
+				}
+				if matched {
+					t.Errorf("Should not match %q", path)
+				}
+			}
+		})
+
+		t.Run("should match negated character classes", func(t *testing.T) {
+			rules = goignore.Rules{}
+
+			for _, line := range []string{"/[!a]", "/src/[!0-9]x", "[b!]y"} {
+				if err := rules.ParseLine(line); err != nil {
+					t.Errorf("Unexpected error: %s", err.Error())
+				}
+			}
+
+			for path, expected := range map[string]bool{
+				"b":      true,
+				"a":      false,
+				"!":      true,
+				"src/ax": true,
+				"src/1x": false,
+				"!y":     true,
+				"ay":     false,
+			} {
+				matched, err := rules.MatchPath(path, false)
+				if err != nil {
+					t.Errorf("Unexpected error: %s", err.Error())
+				} else if matched != expected {
+					t.Errorf("MatchPath(%q) should be %t", path, expected)
+				}
+			}
+		})
+
+		t.Run("should match negated character class prefix", func(t *testing.T) {
+			parsedRules, err := goignore.Parse("[!a]*")
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err.Error())
+			}
+
+			for path, expected := range map[string]bool{"b": true, "bar/a": true, "a": false, "a/a": false} {
+				if matched, err := parsedRules.Match(path); err != nil {
+					t.Errorf("Unexpected error: %s", err.Error())
+				} else if matched != expected {
+					t.Errorf("Match(%q) should be %t", path, expected)
+				}
+			}
+		})
+
+		t.Run("should match directory pattern only for directories", func(t *testing.T) {
+			rules = goignore.Rules{
+				{Raw: "build/", IsDir: true},
+			}
+
+			matched, err := rules.MatchPath("build", true)
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err.Error())
+			}
+			if !matched {
+				t.Errorf("Should match build directory")
+			}
+
+			matched, err = rules.MatchPath("build", false)
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err.Error())
+			}
+			if matched {
+				t.Errorf("Should not match build file")
+			}
+		})
+
+		t.Run("should match negate directory pattern only for directories", func(t *testing.T) {
+			rules = goignore.Rules{
+				{Raw: "build*"},
+				{Raw: "build/", IsNegate: true, IsDir: true},
+			}
+
+			matched, err := rules.MatchPath("build", true)
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err.Error())
+			}
+			if matched {
+				t.Errorf("Should not match build directory")
+			}
+
+			matched, err = rules.MatchPath("build", false)
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err.Error())
+			}
+			if !matched {
+				t.Errorf("Should match build file")
+			}
+		})
+
+		t.Run("should match slash-normalized paths", func(t *testing.T) {
+			rules = goignore.Rules{
+				{Raw: "foo/bar"},
+				{Raw: "baz"},
+			}
+
+			for _, path := range []string{"foo//bar", "./foo/bar", "foo/qux/../bar", "/foo/bar/", "qux/./baz"} {
+				matched, err := rules.MatchPath(path, false)
+				if err != nil {
+					t.Errorf("Unexpected error: %s", err.Error())
+				} else if !matched {
+					t.Errorf("Should match %s", path)
+				}
+			}
+
+			matched, err := rules.MatchPath("../baz/qux", false)
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err.Error())
+			} else if !matched {
+				t.Errorf("Should match ../baz/qux")
+			}
+		})
+
+		t.Run("should escape with backslash on every OS", func(t *testing.T) {
+			rules = goignore.Rules{
+				{Raw: `foo\*`},
+			}
+
+			matched, err := rules.MatchPath("foo*", false)
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err.Error())
+			} else if !matched {
+				t.Errorf("Should match foo*")
+			}
+
+			matched, err = rules.MatchPath("foobar", false)
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err.Error())
+			} else if matched {
+				t.Errorf("Should not match foobar")
+			}
+		})
+	})
+
+	t.Run("MatchFilepath", func(t *testing.T) {
+		t.Run("should match OS-native paths", func(t *testing.T) {
+			rules = goignore.Rules{
+				{Raw: "/foo/bar/"},
+			}
+
+			matched, err := rules.MatchFilepath(filepath.Join("foo", "bar", "baz"), false)
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err.Error())
+			} else if !matched {
+				t.Errorf("Should match foo/bar/baz")
+			}
+		})
+	})
 }
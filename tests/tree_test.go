@@ -0,0 +1,390 @@
+package tests
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	"github.com/dev-addict/goignore"
+)
+
+func TestTree(t *testing.T) {
+	t.Run("MatchPath", func(t *testing.T) {
+		t.Run("should anchor patterns to their directory", func(t *testing.T) {
+			tree := goignore.NewTree(".gitignore")
+			tree.Add("", &goignore.Rules{{Raw: "*.log"}})
+			tree.Add("src", &goignore.Rules{{Raw: "/gen"}})
+
+			matched, err := tree.MatchPath("src/gen", false)
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err.Error())
+			} else if !matched {
+				t.Errorf("Should match src/gen")
+			}
+
+			matched, err = tree.MatchPath("gen", false)
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err.Error())
+			} else if matched {
+				t.Errorf("Should not match gen")
+			}
+
+			matched, err = tree.MatchPath("src/foo/gen", false)
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err.Error())
+			} else if matched {
+				t.Errorf("Should not match src/foo/gen")
+			}
+
+			matched, err = tree.MatchPath("src/debug.log", false)
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err.Error())
+			} else if !matched {
+				t.Errorf("Should match src/debug.log")
+			}
+		})
+
+		t.Run("should let deeper rules override shallower ones", func(t *testing.T) {
+			tree := goignore.NewTree(".gitignore")
+			tree.Add("", &goignore.Rules{{Raw: "*.log"}})
+			tree.Add("src", &goignore.Rules{{Raw: "important.log", IsNegate: true}})
+
+			matched, err := tree.MatchPath("src/important.log", false)
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err.Error())
+			} else if matched {
+				t.Errorf("Should not match src/important.log")
+			}
+
+			matched, err = tree.MatchPath("important.log", false)
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err.Error())
+			} else if !matched {
+				t.Errorf("Should match important.log")
+			}
+		})
+
+		t.Run("should not re-include paths inside ignored directories", func(t *testing.T) {
+			tree := goignore.NewTree(".gitignore")
+			tree.Add("", &goignore.Rules{{Raw: "vendor/", IsDir: true}})
+			tree.Add("vendor/a", &goignore.Rules{{Raw: "keep.go", IsNegate: true}})
+
+			matched, err := tree.MatchPath("vendor/a/keep.go", false)
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err.Error())
+			} else if !matched {
+				t.Errorf("Should match vendor/a/keep.go")
+			}
+		})
+	})
+
+	t.Run("ExplainPath", func(t *testing.T) {
+		tree := goignore.NewTree(".gitignore")
+
+		rootRules, err := goignore.Parse("*.log\nbuild/")
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err.Error())
+		}
+		srcRules, err := goignore.Parse("/x\n!keep.log")
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err.Error())
+		}
+
+		tree.Add("", rootRules)
+		tree.Add("src", srcRules)
+
+		cases := []struct {
+			path    string
+			raw     string
+			ignored bool
+		}{
+			{"src/x", "/x", true},
+			{"src/keep.log", "keep.log", false},
+			{"src/debug.log", "*.log", true},
+			{"build/keep.log", "build/", true},
+			{"x", "", false},
+		}
+
+		for _, c := range cases {
+			explanation, err := tree.ExplainPath(c.path, false)
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err.Error())
+				continue
+			}
+
+			raw := ""
+			if explanation.Pattern != nil {
+				raw = explanation.Pattern.Raw
+			}
+			if raw != c.raw || explanation.Ignored != c.ignored {
+				t.Errorf("%s should be decided by %q (%t), got %q (%t)", c.path, c.raw, c.ignored, raw, explanation.Ignored)
+			}
+		}
+	})
+
+	t.Run("Load", func(t *testing.T) {
+		t.Run("should load ignore file of directory", func(t *testing.T) {
+			root := createTree(t, "src/main.go")
+
+			if err := os.WriteFile(filepath.Join(root, "src", ".gitignore"), []byte("*.go\n"), 0o644); err != nil {
+				t.Fatalf("Unexpected error: %s", err.Error())
+			}
+
+			tree := goignore.NewTree(".gitignore")
+			if err := tree.Load(root, "src"); err != nil {
+				t.Errorf("Unexpected error: %s", err.Error())
+			}
+
+			matched, err := tree.MatchPath("src/main.go", false)
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err.Error())
+			} else if !matched {
+				t.Errorf("Should match src/main.go")
+			}
+		})
+
+		t.Run("should load ignore file of directory from fs", func(t *testing.T) {
+			fsys := fstest.MapFS{
+				"src/.gitignore": {Data: []byte("*.go\n")},
+			}
+
+			tree := goignore.NewTree(".gitignore")
+			if err := tree.LoadFS(fsys, "src"); err != nil {
+				t.Errorf("Unexpected error: %s", err.Error())
+			}
+
+			matched, err := tree.MatchPath("src/main.go", false)
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err.Error())
+			} else if !matched {
+				t.Errorf("Should match src/main.go")
+			}
+		})
+
+		t.Run("should load ignore file with dialect of its name", func(t *testing.T) {
+			fsys := fstest.MapFS{
+				".dockerignore": {Data: []byte("foo\n!foo/keep\n")},
+				".hgignore":     {Data: []byte("\\.orig$\n")},
+			}
+
+			cases := []struct {
+				name    string
+				path    string
+				ignored bool
+			}{
+				{".dockerignore", "foo", true},
+				{".dockerignore", "bar/foo", false},
+				{".dockerignore", "foo/bar", true},
+				{".dockerignore", "foo/keep", false},
+				{".hgignore", "bar/a.orig", true},
+			}
+
+			for _, c := range cases {
+				tree := goignore.NewTree(c.name)
+				if err := tree.LoadFS(fsys, ""); err != nil {
+					t.Errorf("Unexpected error: %s", err.Error())
+					continue
+				}
+
+				matched, err := tree.MatchPath(c.path, false)
+				if err != nil {
+					t.Errorf("Unexpected error: %s", err.Error())
+				} else if matched != c.ignored {
+					t.Errorf("%s: MatchPath(%q) should be %t", c.name, c.path, c.ignored)
+				}
+			}
+		})
+
+		t.Run("should skip invalid lines and collect warnings", func(t *testing.T) {
+			fsys := fstest.MapFS{
+				"src/.gitignore": {Data: []byte("[123\n*.go\n")},
+			}
+
+			var warnings goignore.ParseErrors
+
+			tree := goignore.NewTree(".gitignore")
+			tree.Options.Warnings = &warnings
+
+			if err := tree.LoadFS(fsys, "src"); err != nil {
+				t.Fatalf("Unexpected error: %s", err.Error())
+			}
+
+			if len(warnings) != 1 || warnings[0].Source != "src/.gitignore" || warnings[0].Line != 1 {
+				t.Errorf("Expected warning of src/.gitignore:1, got %v", warnings)
+			}
+
+			matched, err := tree.MatchPath("src/main.go", false)
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err.Error())
+			} else if !matched {
+				t.Errorf("Should match src/main.go")
+			}
+		})
+
+		t.Run("should not skip invalid lines when strict", func(t *testing.T) {
+			fsys := fstest.MapFS{
+				"src/.gitignore": {Data: []byte("[123\n*.go\n")},
+			}
+
+			tree := goignore.NewTree(".gitignore")
+			tree.Options.Lenient = false
+
+			if err := tree.LoadFS(fsys, "src"); !errors.Is(err, goignore.ErrBadPattern) {
+				t.Errorf("Expected error %s, got %v", goignore.ErrBadPattern.Error(), err)
+			}
+		})
+
+		t.Run("should not fail without ignore file", func(t *testing.T) {
+			root := createTree(t, "src/main.go")
+
+			tree := goignore.NewTree(".gitignore")
+			if err := tree.Load(root, "src"); err != nil {
+				t.Errorf("Unexpected error: %s", err.Error())
+			}
+		})
+	})
+}
+
+func TestWalkTree(t *testing.T) {
+	t.Run("should apply ignore files of every directory", func(t *testing.T) {
+		root := createTree(t,
+			"main.go",
+			"debug.log",
+			"src/app.go",
+			"src/app.log",
+			"src/important.log",
+			"src/gen/app.go",
+			"src/pkg/gen/app.go",
+			"tmp/gen/app.go",
+		)
+
+		ignoreFiles := map[string]string{
+			".gitignore":         "*.log\n/tmp/\n",
+			"src/.gitignore":     "!important.log\n/gen/\n",
+			"tmp/gen/.gitignore": "!app.go\n",
+			"src/pkg/.gitignore": "\n",
+		}
+
+		for name, content := range ignoreFiles {
+			if err := os.WriteFile(filepath.Join(root, filepath.FromSlash(name)), []byte(content), 0o644); err != nil {
+				t.Fatalf("Unexpected error: %s", err.Error())
+			}
+		}
+
+		var walked []string
+
+		err := goignore.WalkTree(root, goignore.NewTree(".gitignore"), func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if d.IsDir() {
+				return nil
+			}
+
+			rel, err := filepath.Rel(root, path)
+			if err != nil {
+				return err
+			}
+
+			walked = append(walked, filepath.ToSlash(rel))
+			return nil
+		})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err.Error())
+		}
+
+		expected := []string{
+			".gitignore",
+			"main.go",
+			"src/.gitignore",
+			"src/app.go",
+			"src/important.log",
+			"src/pkg/.gitignore",
+			"src/pkg/gen/app.go",
+		}
+		if !reflect.DeepEqual(expected, walked) {
+			t.Errorf("Expected %v, got %v", expected, walked)
+		}
+	})
+}
+
+func TestWalkTreeFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		".gitignore":             {Data: []byte("*.log\n/tmp/\n.env\n")},
+		".env":                   {},
+		"main.go":                {},
+		"debug.log":              {},
+		"src/.gitignore":         {Data: []byte("!important.log\n/gen/\n")},
+		"src/app.go":             {},
+		"src/app.log":            {},
+		"src/important.log":      {},
+		"src/gen/app.go":         {},
+		"src/pkg/gen/app.go":     {},
+		"tmp/gen/.gitignore":     {Data: []byte("!app.go\n")},
+		"tmp/gen/app.go":         {},
+		"src/pkg/.gitignore":     {Data: []byte("/*.go\n")},
+		"src/pkg/pkg.go":         {},
+		"src/pkg/sub/sub.go":     {},
+		"src/pkg/sub/.gitignore": {Data: []byte("[123\n")},
+	}
+
+	walk := func(root string) []string {
+		var walked []string
+
+		err := goignore.WalkTreeFS(fsys, root, goignore.NewTree(".gitignore"), func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if !d.IsDir() {
+				walked = append(walked, path)
+			}
+			return nil
+		})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err.Error())
+		}
+
+		return walked
+	}
+
+	t.Run("should apply ignore files of every directory", func(t *testing.T) {
+		expected := []string{
+			".gitignore",
+			"main.go",
+			"src/.gitignore",
+			"src/app.go",
+			"src/important.log",
+			"src/pkg/.gitignore",
+			"src/pkg/gen/app.go",
+			"src/pkg/sub/.gitignore",
+			"src/pkg/sub/sub.go",
+		}
+
+		if walked := walk("."); !reflect.DeepEqual(expected, walked) {
+			t.Errorf("Expected %v, got %v", expected, walked)
+		}
+	})
+
+	t.Run("should anchor ignore files to root", func(t *testing.T) {
+		expected := []string{
+			"src/.gitignore",
+			"src/app.go",
+			"src/app.log",
+			"src/important.log",
+			"src/pkg/.gitignore",
+			"src/pkg/gen/app.go",
+			"src/pkg/sub/.gitignore",
+			"src/pkg/sub/sub.go",
+		}
+
+		if walked := walk("src"); !reflect.DeepEqual(expected, walked) {
+			t.Errorf("Expected %v, got %v", expected, walked)
+		}
+	})
+}
@@ -0,0 +1,218 @@
+package tests
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	"github.com/dev-addict/goignore"
+)
+
+func createTree(t *testing.T, files ...string) string {
+	t.Helper()
+
+	root := t.TempDir()
+
+	for _, file := range files {
+		path := filepath.Join(root, filepath.FromSlash(file))
+
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("Unexpected error: %s", err.Error())
+		}
+
+		if err := os.WriteFile(path, nil, 0o644); err != nil {
+			t.Fatalf("Unexpected error: %s", err.Error())
+		}
+	}
+
+	return root
+}
+
+func TestWalk(t *testing.T) {
+	t.Run("should skip ignored files and directories", func(t *testing.T) {
+		root := createTree(t,
+			"main.go",
+			"debug.log",
+			"build/main",
+			"src/app.go",
+			"src/app.log",
+			"src/build/app",
+			"vendor/a/b.go",
+		)
+
+		rules, err := goignore.Parse("*.log\nbuild/\n/vendor")
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err.Error())
+		}
+
+		var walked []string
+
+		err = goignore.Walk(root, rules, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			rel, err := filepath.Rel(root, path)
+			if err != nil {
+				return err
+			}
+
+			walked = append(walked, filepath.ToSlash(rel))
+			return nil
+		})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err.Error())
+		}
+
+		expected := []string{".", "main.go", "src", "src/app.go"}
+		if !reflect.DeepEqual(expected, walked) {
+			t.Errorf("Expected %v, got %v", expected, walked)
+		}
+	})
+
+	t.Run("should match directory patterns only against directories", func(t *testing.T) {
+		root := createTree(t,
+			"build",
+			"out/build/main",
+		)
+
+		rules, err := goignore.Parse("build/")
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err.Error())
+		}
+
+		var walked []string
+
+		err = goignore.Walk(root, rules, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			rel, err := filepath.Rel(root, path)
+			if err != nil {
+				return err
+			}
+
+			walked = append(walked, filepath.ToSlash(rel))
+			return nil
+		})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err.Error())
+		}
+
+		expected := []string{".", "build", "out"}
+		if !reflect.DeepEqual(expected, walked) {
+			t.Errorf("Expected %v, got %v", expected, walked)
+		}
+	})
+
+	t.Run("should pass walk errors to the function", func(t *testing.T) {
+		err := goignore.Walk(filepath.Join(t.TempDir(), "missing"), &goignore.Rules{}, func(path string, d fs.DirEntry, err error) error {
+			return err
+		})
+		if err == nil {
+			t.Errorf("Expected error, got nil")
+		}
+	})
+}
+
+func TestWalkFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"main.go":          {},
+		"debug.log":        {},
+		"build/main":       {},
+		"src/app.go":       {},
+		"src/app.log":      {},
+		"src/build/app":    {},
+		"vendor/a/b.go":    {},
+		"assets/build":     {},
+		"assets/style.css": {},
+	}
+
+	t.Run("should skip ignored files and directories", func(t *testing.T) {
+		rules, err := goignore.Parse("*.log\nbuild/\n/vendor")
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err.Error())
+		}
+
+		var walked []string
+
+		err = goignore.WalkFS(fsys, ".", rules, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			walked = append(walked, path)
+			return nil
+		})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err.Error())
+		}
+
+		expected := []string{".", "assets", "assets/build", "assets/style.css", "main.go", "src", "src/app.go"}
+		if !reflect.DeepEqual(expected, walked) {
+			t.Errorf("Expected %v, got %v", expected, walked)
+		}
+	})
+
+	t.Run("should match dotfiles at the root", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			".env":      {},
+			".git/HEAD": {},
+			"main.go":   {},
+		}
+
+		rules, err := goignore.Parse(".git/\n.env")
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err.Error())
+		}
+
+		var walked []string
+
+		err = goignore.WalkFS(fsys, ".", rules, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			walked = append(walked, path)
+			return nil
+		})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err.Error())
+		}
+
+		expected := []string{".", "main.go"}
+		if !reflect.DeepEqual(expected, walked) {
+			t.Errorf("Expected %v, got %v", expected, walked)
+		}
+	})
+
+	t.Run("should match paths relative to root", func(t *testing.T) {
+		rules, err := goignore.Parse("/app.log")
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err.Error())
+		}
+
+		var walked []string
+
+		err = goignore.WalkFS(fsys, "src", rules, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			walked = append(walked, path)
+			return nil
+		})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err.Error())
+		}
+
+		expected := []string{"src", "src/app.go", "src/build", "src/build/app"}
+		if !reflect.DeepEqual(expected, walked) {
+			t.Errorf("Expected %v, got %v", expected, walked)
+		}
+	})
+}
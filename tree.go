@@ -0,0 +1,277 @@
+package goignore
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Tree is safe for concurrent use, so ignore files can be loaded while other directories are matched.
+type Tree struct {
+	Name string
+
+	// Options are used to parse the ignore files. A nil Dialect is the Dialect of Name, and the warnings of every
+	// ignore file are appended to Warnings.
+	Options ParseOptions
+
+	mu    sync.RWMutex
+	rules map[string]*Rules
+}
+
+// NewTree returns a Tree which skips the invalid lines of its ignore files, like git does.
+func NewTree(name string) *Tree {
+	return &Tree{
+		Name:    name,
+		Options: ParseOptions{Lenient: true},
+		rules:   map[string]*Rules{},
+	}
+}
+
+func (t *Tree) Add(dir string, rules *Rules) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.rules == nil {
+		t.rules = map[string]*Rules{}
+	}
+
+	dir = cleanDir(dir)
+
+	if existing, ok := t.rules[dir]; ok {
+		merged := append(append(Rules{}, *existing...), *rules...)
+		t.rules[dir] = &merged
+		return
+	}
+
+	t.rules[dir] = rules
+}
+
+// Load parses the ignore file of the directory, if any, with the Dialect of its name, e.g. DialectDocker for
+// ".dockerignore", or DialectGit for unknown names.
+func (t *Tree) Load(root, dir string) error {
+	var warnings ParseErrors
+
+	name := filepath.Join(root, filepath.FromSlash(cleanDir(dir)), t.Name)
+
+	rules, err := ParseFileFromPathWithOptions(name, t.parseOptions(&warnings))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	t.Add(dir, rules)
+	t.warn(warnings)
+	return nil
+}
+
+func (t *Tree) LoadFS(fsys fs.FS, dir string) error {
+	name := path.Join(cleanDir(dir), t.Name)
+
+	file, err := fsys.Open(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	var warnings ParseErrors
+
+	rules, err := parseFile(context.Background(), file, name, t.parseOptions(&warnings))
+	if err != nil {
+		return err
+	}
+
+	t.Add(dir, rules)
+	t.warn(warnings)
+	return nil
+}
+
+// parseOptions returns the Options of the Tree, which collect the warnings of one ignore file.
+func (t *Tree) parseOptions(warnings *ParseErrors) ParseOptions {
+	options := t.Options
+	options.Warnings = warnings
+
+	if options.Dialect == nil {
+		dialect, ok := LookupDialect(path.Base(t.Name))
+		if !ok {
+			dialect = DialectGit
+		}
+		options.Dialect = dialect
+	}
+
+	return options
+}
+
+// warn appends the warnings to the Options while the Tree is locked, so ignore files can be loaded concurrently.
+func (t *Tree) warn(warnings ParseErrors) {
+	if len(warnings) == 0 || t.Options.Warnings == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	*t.Options.Warnings = append(*t.Options.Warnings, warnings...)
+}
+
+func (t *Tree) Match(path string) (bool, error) {
+	return t.MatchPath(path, strings.HasSuffix(path, "/"))
+}
+
+func (t *Tree) MatchPath(path string, isDir bool) (bool, error) {
+	target := newTarget(path, isDir)
+
+	for i := 1; i < len(target.segments); i++ {
+		ignored, err := t.match(target.parent(i), false)
+		if err != nil || ignored {
+			return ignored, err
+		}
+	}
+
+	return t.match(target, true)
+}
+
+// ExplainPath is like Rules.ExplainPath. Index is the index of the Pattern in the Rules of its directory, and Matches
+// are the matching patterns of every directory.
+func (t *Tree) ExplainPath(path string, isDir bool) (Explanation, error) {
+	target := newTarget(path, isDir)
+
+	for i := 1; i <= len(target.segments); i++ {
+		level := target
+		if i < len(target.segments) {
+			level = target.parent(i)
+		}
+
+		explanation, err := t.explain(level, i == len(target.segments))
+		if err != nil {
+			return Explanation{Index: -1}, err
+		}
+
+		// A parent directory only decides when it is ignored, because its children cannot be re-included.
+		if explanation.Ignored || i == len(target.segments) {
+			return explanation, nil
+		}
+	}
+
+	return Explanation{Index: -1}, nil
+}
+
+// MatchFilepath is like MatchPath, but accepts an OS-native path.
+func (t *Tree) MatchFilepath(path string, isDir bool) (bool, error) {
+	return t.MatchPath(filepath.ToSlash(path), isDir)
+}
+
+// match evaluates the rules of every directory above the path, from the root down, so the
+// patterns of deeper ignore files override the shallower ones. Only the git precedence matches
+// the parent directories of a path, the other dialects match them by themselves.
+func (t *Tree) match(target target, leaf bool) (bool, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	ignored := false
+
+	for i := 0; i < len(target.segments); i++ {
+		dir := ""
+		if i > 0 {
+			dir = target.parent(i).path
+		}
+
+		rules, ok := t.rules[dir]
+		if !ok || (!leaf && rules.dialect().Precedence() != PrecedenceGit) {
+			continue
+		}
+
+		index, err := rules.last(target.child(i))
+		if err != nil {
+			return false, err
+		}
+		if index >= 0 {
+			ignored = !(*rules)[index].IsNegate
+		}
+	}
+
+	return ignored, nil
+}
+
+// explain returns the last pattern matching the path, from the rules of the root down like match.
+func (t *Tree) explain(target target, leaf bool) (Explanation, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	explanation := Explanation{Index: -1}
+
+	for i := 0; i < len(target.segments); i++ {
+		dir := ""
+		if i > 0 {
+			dir = target.parent(i).path
+		}
+
+		rules, ok := t.rules[dir]
+		if !ok || (!leaf && rules.dialect().Precedence() != PrecedenceGit) {
+			continue
+		}
+
+		child := target.child(i)
+		for j := range *rules {
+			matched, err := (*rules)[j].match(child)
+			if err != nil {
+				return Explanation{Index: -1}, err
+			}
+			if !matched {
+				continue
+			}
+
+			explanation.Matches = append(explanation.Matches, (*rules)[j])
+			explanation.Index = j
+			explanation.Pattern = &(*rules)[j]
+			explanation.IsNegate = explanation.Pattern.IsNegate
+			explanation.Ignored = !explanation.IsNegate
+		}
+	}
+
+	return explanation, nil
+}
+
+func WalkTree(root string, tree *Tree, fn fs.WalkDirFunc) error {
+	return WalkTreeContext(context.Background(), root, tree, fn)
+}
+
+// WalkTreeContext is like WalkTree, but stops with ctx.Err() when the context is cancelled.
+func WalkTreeContext(ctx context.Context, root string, tree *Tree, fn fs.WalkDirFunc) error {
+	return filepath.WalkDir(root, contextWalkDirFunc(ctx, walkDirFunc(osRel(root), tree, func(dir string) error {
+		return tree.Load(root, dir)
+	}, fn)))
+}
+
+func WalkTreeFS(fsys fs.FS, root string, tree *Tree, fn fs.WalkDirFunc) error {
+	return WalkTreeFSContext(context.Background(), fsys, root, tree, fn)
+}
+
+func WalkTreeFSContext(ctx context.Context, fsys fs.FS, root string, tree *Tree, fn fs.WalkDirFunc) error {
+	sub, err := fs.Sub(fsys, root)
+	if err != nil {
+		return err
+	}
+
+	return fs.WalkDir(fsys, root, contextWalkDirFunc(ctx, walkDirFunc(fsRel(root), tree, func(dir string) error {
+		return tree.LoadFS(sub, dir)
+	}, fn)))
+}
+
+func cleanDir(dir string) string {
+	dir = strings.Trim(filepath.ToSlash(filepath.Clean(dir)), "/")
+	if dir == "." {
+		return ""
+	}
+
+	return dir
+}
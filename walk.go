@@ -0,0 +1,99 @@
+package goignore
+
+import (
+	"context"
+	"io/fs"
+	"path/filepath"
+	"strings"
+)
+
+type pathMatcher interface {
+	MatchPath(path string, isDir bool) (bool, error)
+}
+
+func Walk(root string, rules *Rules, fn fs.WalkDirFunc) error {
+	return WalkContext(context.Background(), root, rules, fn)
+}
+
+// WalkContext is like Walk, but stops with ctx.Err() when the context is cancelled.
+func WalkContext(ctx context.Context, root string, rules *Rules, fn fs.WalkDirFunc) error {
+	return filepath.WalkDir(root, contextWalkDirFunc(ctx, walkDirFunc(osRel(root), rules, nil, fn)))
+}
+
+func WalkFS(fsys fs.FS, root string, rules *Rules, fn fs.WalkDirFunc) error {
+	return WalkFSContext(context.Background(), fsys, root, rules, fn)
+}
+
+func WalkFSContext(ctx context.Context, fsys fs.FS, root string, rules *Rules, fn fs.WalkDirFunc) error {
+	return fs.WalkDir(fsys, root, contextWalkDirFunc(ctx, walkDirFunc(fsRel(root), rules, nil, fn)))
+}
+
+// contextWalkDirFunc checks the context before every entry is matched and visited.
+func contextWalkDirFunc(ctx context.Context, fn fs.WalkDirFunc) fs.WalkDirFunc {
+	return func(path string, d fs.DirEntry, err error) error {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+
+		return fn(path, d, err)
+	}
+}
+
+func walkDirFunc(rel func(string) (string, error), matcher pathMatcher, load func(string) error, fn fs.WalkDirFunc) fs.WalkDirFunc {
+	return func(path string, d fs.DirEntry, err error) error {
+		if d == nil {
+			return fn(path, d, err)
+		}
+
+		name, relErr := rel(path)
+		if relErr != nil {
+			return relErr
+		}
+
+		if name != "" {
+			ignored, matchErr := matcher.MatchPath(name, d.IsDir())
+			if matchErr != nil {
+				return matchErr
+			}
+
+			if ignored {
+				if d.IsDir() {
+					return fs.SkipDir
+				}
+				return nil
+			}
+		}
+
+		if load != nil && d.IsDir() && err == nil {
+			if loadErr := load(name); loadErr != nil {
+				return loadErr
+			}
+		}
+
+		return fn(path, d, err)
+	}
+}
+
+func osRel(root string) func(string) (string, error) {
+	return func(path string) (string, error) {
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return "", err
+		}
+
+		return cleanDir(rel), nil
+	}
+}
+
+func fsRel(root string) func(string) (string, error) {
+	return func(path string) (string, error) {
+		if root == "." {
+			return cleanDir(path), nil
+		}
+		if path == root {
+			return "", nil
+		}
+
+		return strings.TrimPrefix(path, root+"/"), nil
+	}
+}